@@ -0,0 +1,148 @@
+// Package achievements implements an offline achievements engine. Achievements
+// are defined in JSON files as sets of conditions over the memory of the
+// emulated system, evaluated on each frame. Unlocks are saved per game.
+package achievements
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/libretro/ludo/libretro"
+	ntf "github.com/libretro/ludo/notifications"
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/utils"
+)
+
+// Set is the content of an achievements definition file
+type Set struct {
+	Game         string         `json:"game"`
+	Achievements []*Achievement `json:"achievements"`
+}
+
+var (
+	current *engine
+	memView memory            // memory of the core, built once per game
+	game    string            // file name of the current game
+	unlocks map[int]time.Time // unlock dates of the current game by achievement ID
+)
+
+// definitionsPath returns the path of the achievements definition file
+func definitionsPath(name string) string {
+	return filepath.Join(settings.Current.AchievementsDirectory, name+".json")
+}
+
+// unlocksPath returns the path of the file storing the unlocks of a game
+func unlocksPath(name string) string {
+	return filepath.Join(settings.Current.AchievementsDirectory, name+".unlocks.json")
+}
+
+// Load reads the achievements definitions for the game, and the achievements
+// already unlocked. Games without definitions are ignored.
+func Load(gamePath string) error {
+	Reset()
+
+	name := utils.FileName(gamePath)
+	b, err := ioutil.ReadFile(definitionsPath(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var set Set
+	if err := json.Unmarshal(b, &set); err != nil {
+		return err
+	}
+
+	unlocks = map[int]time.Time{}
+	b, err = ioutil.ReadFile(unlocksPath(name))
+	if err == nil {
+		if err := json.Unmarshal(b, &unlocks); err != nil {
+			return err
+		}
+	}
+	for _, a := range set.Achievements {
+		_, a.Unlocked = unlocks[a.ID]
+	}
+
+	game = name
+	current = newEngine(set.Achievements)
+	memView = coreMemory()
+	return nil
+}
+
+// Reset stops watching the memory of the current game
+func Reset() {
+	current = nil
+	memView = nil
+	game = ""
+	unlocks = nil
+}
+
+// List returns the achievements of the current game
+func List() []*Achievement {
+	if current == nil {
+		return nil
+	}
+	return current.achievements
+}
+
+// save writes the unlocks of the current game
+func save() error {
+	b, err := json.MarshalIndent(unlocks, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(settings.Current.AchievementsDirectory, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(unlocksPath(game), b, 0644)
+}
+
+// UpdateMemory rebuilds the view of the core memory. It has to be called when
+// the core changes its memory map.
+func UpdateMemory() {
+	if current == nil {
+		return
+	}
+	memView = coreMemory()
+}
+
+// coreMemory returns the memory of the core, using the memory map if the core
+// exposes one, or the system RAM otherwise
+func coreMemory() memory {
+	if len(state.Core.MemoryMap) > 0 {
+		return newMemoryMap(state.Core.MemoryMap)
+	}
+
+	size := int(state.Core.GetMemorySize(libretro.MemorySystemRAM))
+	data := state.Core.GetMemoryData(libretro.MemorySystemRAM)
+	if size == 0 || data == nil {
+		return ram{}
+	}
+	// this *[1 << 30]byte points to the same memory as data, allowing to
+	// read the core memory without copying it
+	return ram((*[1 << 30]byte)(data)[:size:size])
+}
+
+// Frame evaluates the achievements. It has to be called after each frame run
+// by the core.
+func Frame() {
+	if current == nil {
+		return
+	}
+
+	for _, a := range current.frame(memView) {
+		unlocks[a.ID] = time.Now()
+		ntf.DisplayAndLog(ntf.Success, "Achievements", "Unlocked: %s.", a.Title)
+		if err := save(); err != nil {
+			ntf.DisplayAndLog(ntf.Error, "Achievements", err.Error())
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package achievements
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/libretro/ludo/libretro"
+)
+
+func mem(address uint32) Operand {
+	return Operand{Type: "mem", Size: "8bit", Address: address}
+}
+
+func value(v uint32) Operand {
+	return Operand{Type: "value", Value: v}
+}
+
+func Test_engine(t *testing.T) {
+	t.Run("Needs the achievement to be false once before unlocking", func(t *testing.T) {
+		a := &Achievement{Conditions: []Condition{{Left: mem(0), Op: "==", Right: value(1)}}}
+		e := newEngine([]*Achievement{a})
+		if got := e.frame(ram{1}); len(got) != 0 {
+			t.Errorf("got = %v, want %v", len(got), 0)
+		}
+		e.frame(ram{0})
+		if got := e.frame(ram{1}); len(got) != 1 || !a.Unlocked {
+			t.Errorf("got = %v, want %v", len(got), 1)
+		}
+		if got := e.frame(ram{1}); len(got) != 0 {
+			t.Errorf("got = %v, want %v", len(got), 0)
+		}
+	})
+
+	t.Run("Compares with the value of the previous frame", func(t *testing.T) {
+		a := &Achievement{Conditions: []Condition{
+			{Left: mem(0), Op: ">", Right: Operand{Type: "delta", Size: "8bit", Address: 0}},
+		}}
+		e := newEngine([]*Achievement{a})
+		e.frame(ram{5})
+		e.frame(ram{5})
+		if got := e.frame(ram{6}); len(got) != 1 {
+			t.Errorf("got = %v, want %v", len(got), 1)
+		}
+	})
+
+	t.Run("Counts hits and resets them", func(t *testing.T) {
+		a := &Achievement{Conditions: []Condition{
+			{Left: mem(0), Op: "==", Right: value(1), Hits: 3},
+			{Flag: "reset_if", Left: mem(1), Op: "==", Right: value(1)},
+		}}
+		e := newEngine([]*Achievement{a})
+		e.frame(ram{1, 0})
+		e.frame(ram{1, 0})
+		e.frame(ram{1, 1}) // reset
+		e.frame(ram{1, 0})
+		if got := e.frame(ram{1, 0}); len(got) != 0 {
+			t.Errorf("got = %v, want %v", len(got), 0)
+		}
+		if got := e.frame(ram{1, 0}); len(got) != 1 {
+			t.Errorf("got = %v, want %v", len(got), 1)
+		}
+	})
+
+	t.Run("Pauses the hit counts", func(t *testing.T) {
+		a := &Achievement{Conditions: []Condition{
+			{Left: mem(0), Op: "==", Right: value(1), Hits: 2},
+			{Flag: "pause_if", Left: mem(1), Op: "==", Right: value(1)},
+		}}
+		e := newEngine([]*Achievement{a})
+		e.frame(ram{1, 0})
+		e.frame(ram{1, 1})
+		e.frame(ram{1, 1})
+		if a.Unlocked {
+			t.Errorf("got = %v, want %v", a.Unlocked, false)
+		}
+		if got := e.frame(ram{1, 0}); len(got) != 1 {
+			t.Errorf("got = %v, want %v", len(got), 1)
+		}
+	})
+
+	t.Run("Needs one of the alternates", func(t *testing.T) {
+		a := &Achievement{
+			Conditions: []Condition{{Left: mem(0), Op: "==", Right: value(1)}},
+			Alternates: [][]Condition{
+				{{Left: mem(1), Op: "==", Right: value(2)}},
+				{{Left: mem(1), Op: "==", Right: value(3)}},
+			},
+		}
+		e := newEngine([]*Achievement{a})
+		e.frame(ram{0, 0})
+		if got := e.frame(ram{1, 0}); len(got) != 0 {
+			t.Errorf("got = %v, want %v", len(got), 0)
+		}
+		if got := e.frame(ram{1, 3}); len(got) != 1 {
+			t.Errorf("got = %v, want %v", len(got), 1)
+		}
+	})
+}
+
+func Test_read(t *testing.T) {
+	m := ram{0x34, 0x12, 0xa5}
+	tests := []struct {
+		size    string
+		address uint32
+		want    uint32
+	}{
+		{"8bit", 0, 0x34},
+		{"16bit", 0, 0x1234},
+		{"24bit", 0, 0xa51234},
+		{"bit2", 0, 1},
+		{"bit3", 0, 0},
+		{"lower4", 2, 0x5},
+		{"upper4", 2, 0xa},
+		{"16bit", 2, 0xa5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got := read(m, tt.address, tt.size)
+			if got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_memoryMap(t *testing.T) {
+	wram := []byte{1, 2, 3, 4}
+	sram := []byte{5, 6, 7, 8, 9, 10, 11, 12}
+	m := newMemoryMap([]libretro.MemoryDescriptor{
+		// 4 bytes mirrored at 0x0000-0x00ff
+		{Ptr: unsafe.Pointer(&wram[0]), Start: 0x0000, Select: 0xff00, Len: 4},
+		// 4 bytes at 0x1000, skipping the first 4 bytes of sram
+		{Ptr: unsafe.Pointer(&sram[0]), Offset: 4, Start: 0x1000, Len: 4},
+	})
+
+	tests := []struct {
+		address uint32
+		want    byte
+		ok      bool
+	}{
+		{0x0001, 2, true},
+		{0x0005, 2, true},
+		{0x1000, 9, true},
+		{0x1003, 12, true},
+		{0x1004, 0, false},
+		{0x2000, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := m.peek(tt.address)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("peek(%x) = %v, %v, want %v, %v", tt.address, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func Test_reduce(t *testing.T) {
+	t.Run("Removes the disconnected bits", func(t *testing.T) {
+		got := reduce(0x1234, 0x0f00)
+		if got != 0x134 {
+			t.Errorf("got = %x, want %x", got, 0x134)
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package achievements
+
+// Operand is one side of a condition
+type Operand struct {
+	Type    string `json:"type"`    // "mem", "delta" or "value"
+	Size    string `json:"size"`    // "bit0" to "bit7", "lower4", "upper4", "8bit", "16bit", "24bit" or "32bit"
+	Address uint32 `json:"address"` // address in the emulated address space
+	Value   uint32 `json:"value"`   // constant used by "value" operands
+}
+
+// Condition compares two operands
+type Condition struct {
+	Flag  string  `json:"flag"` // "", "reset_if" or "pause_if"
+	Left  Operand `json:"left"`
+	Op    string  `json:"op"` // "==", "!=", "<", "<=", ">" or ">="
+	Right Operand `json:"right"`
+	Hits  uint    `json:"hits"` // number of frames the condition has to be true, 0 means true on the current frame
+
+	hits uint // hits counted so far
+}
+
+// Achievement is a set of conditions to satisfy
+type Achievement struct {
+	ID          int           `json:"id"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Conditions  []Condition   `json:"conditions"`
+	Alternates  [][]Condition `json:"alternates"` // at least one of them has to be true too
+
+	Unlocked bool `json:"-"`
+	primed   bool // the achievement was false at least once, so it can trigger
+}
+
+// memRef identifies a memory value watched by the engine
+type memRef struct {
+	address uint32
+	size    string
+}
+
+// engine evaluates the achievements of a game frame by frame
+type engine struct {
+	achievements []*Achievement
+	refs         map[memRef]bool
+	cur, prev    map[memRef]uint32
+}
+
+func newEngine(list []*Achievement) *engine {
+	e := &engine{
+		achievements: list,
+		refs:         map[memRef]bool{},
+	}
+	for _, a := range list {
+		for _, group := range append([][]Condition{a.Conditions}, a.Alternates...) {
+			for _, c := range group {
+				for _, o := range []Operand{c.Left, c.Right} {
+					if o.Type != "value" {
+						e.refs[memRef{o.Address, o.Size}] = true
+					}
+				}
+			}
+		}
+	}
+	return e
+}
+
+// read returns the value of size bytes at address, little endian
+func read(m memory, address uint32, size string) uint32 {
+	n := 1
+	switch size {
+	case "16bit":
+		n = 2
+	case "24bit":
+		n = 3
+	case "32bit":
+		n = 4
+	}
+
+	var v uint32
+	for i := 0; i < n; i++ {
+		b, _ := m.peek(address + uint32(i))
+		v |= uint32(b) << uint(8*i)
+	}
+
+	switch size {
+	case "bit0", "bit1", "bit2", "bit3", "bit4", "bit5", "bit6", "bit7":
+		v = v >> uint(size[3]-'0') & 1
+	case "lower4":
+		v &= 0xf
+	case "upper4":
+		v >>= 4
+	}
+	return v
+}
+
+func (e *engine) value(o Operand) uint32 {
+	switch o.Type {
+	case "mem":
+		return e.cur[memRef{o.Address, o.Size}]
+	case "delta":
+		return e.prev[memRef{o.Address, o.Size}]
+	}
+	return o.Value
+}
+
+func compare(l uint32, op string, r uint32) bool {
+	switch op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	}
+	return false
+}
+
+// group evaluates a group of conditions, updating the hit counts. It returns
+// the truth of the group and whether a reset_if condition was hit.
+func (e *engine) group(conds []Condition) (bool, bool) {
+	for i := range conds {
+		c := &conds[i]
+		if c.Flag == "pause_if" && compare(e.value(c.Left), c.Op, e.value(c.Right)) {
+			return false, false
+		}
+	}
+
+	ok := true
+	reset := false
+	for i := range conds {
+		c := &conds[i]
+		if c.Flag == "pause_if" {
+			continue
+		}
+		res := compare(e.value(c.Left), c.Op, e.value(c.Right))
+		if c.Flag == "reset_if" {
+			if res {
+				reset = true
+			}
+			continue
+		}
+		if res && c.Hits > 0 && c.hits < c.Hits {
+			c.hits++
+		}
+		if c.Hits > 0 {
+			res = c.hits >= c.Hits
+		}
+		ok = ok && res
+	}
+	return ok && !reset, reset
+}
+
+// resetHits sets the hit counts of an achievement back to zero
+func resetHits(a *Achievement) {
+	for _, group := range append([][]Condition{a.Conditions}, a.Alternates...) {
+		for i := range group {
+			group[i].hits = 0
+		}
+	}
+}
+
+// frame reads the memory and evaluates the achievements. It returns the
+// achievements unlocked during this frame.
+func (e *engine) frame(m memory) []*Achievement {
+	cur := map[memRef]uint32{}
+	for r := range e.refs {
+		cur[r] = read(m, r.address, r.size)
+	}
+	e.prev = e.cur
+	if e.prev == nil {
+		e.prev = cur
+	}
+	e.cur = cur
+
+	unlocked := []*Achievement{}
+	for _, a := range e.achievements {
+		if a.Unlocked {
+			continue
+		}
+
+		ok, reset := e.group(a.Conditions)
+		if len(a.Alternates) > 0 {
+			anyAlt := false
+			for _, alt := range a.Alternates {
+				altOk, altReset := e.group(alt)
+				anyAlt = anyAlt || altOk
+				reset = reset || altReset
+			}
+			ok = ok && anyAlt
+		}
+		if reset {
+			resetHits(a)
+		}
+
+		if !ok {
+			a.primed = true
+			continue
+		}
+		if a.primed {
+			a.Unlocked = true
+			unlocked = append(unlocked, a)
+		}
+	}
+	return unlocked
+}
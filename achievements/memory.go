@@ -0,0 +1,76 @@
+package achievements
+
+import (
+	"unsafe"
+
+	"github.com/libretro/ludo/libretro"
+)
+
+// memory gives access to the emulated address space
+type memory interface {
+	peek(address uint32) (byte, bool)
+}
+
+// ram is a flat view of the system RAM of the core
+type ram []byte
+
+func (m ram) peek(address uint32) (byte, bool) {
+	if int(address) >= len(m) {
+		return 0, false
+	}
+	return m[address], true
+}
+
+// region is a memory descriptor with its backing memory
+type region struct {
+	libretro.MemoryDescriptor
+	data []byte
+}
+
+// memoryMap resolves addresses using the memory descriptors of the core
+type memoryMap []region
+
+func newMemoryMap(descs []libretro.MemoryDescriptor) memoryMap {
+	m := memoryMap{}
+	for _, d := range descs {
+		if d.Ptr == nil || d.Len == 0 {
+			continue
+		}
+		// this *[1 << 30]byte points to the same memory as d.Ptr, allowing to
+		// read the core memory without copying it
+		data := (*[1 << 30]byte)(unsafe.Pointer(uintptr(d.Ptr) + uintptr(d.Offset)))[:d.Len:d.Len]
+		m = append(m, region{d, data})
+	}
+	return m
+}
+
+// reduce removes the disconnected bits from an address
+func reduce(addr, mask uint) uint {
+	for mask != 0 {
+		tmp := (mask - 1) & ^mask
+		addr = (addr & tmp) | ((addr >> 1) & ^tmp)
+		mask = (mask & (mask - 1)) >> 1
+	}
+	return addr
+}
+
+func (m memoryMap) peek(address uint32) (byte, bool) {
+	addr := uint(address)
+	for _, r := range m {
+		if r.Select != 0 {
+			if addr&r.Select != r.Start&r.Select {
+				continue
+			}
+		} else if addr < r.Start || addr >= r.Start+r.Len {
+			continue
+		}
+
+		offset := reduce(addr-r.Start, r.Disconnect)
+		if offset >= uint(len(r.data)) {
+			// Mirrored regions
+			offset %= uint(len(r.data))
+		}
+		return r.data[offset], true
+	}
+	return 0, false
+}
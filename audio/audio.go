@@ -1,29 +1,27 @@
-// Package audio uses OpenAL to play game audio by exposing the two audio
-// callbacks Sample and SampleBatch for the libretro implementation.
+// Package audio plays game audio by exposing the two audio callbacks Sample
+// and SampleBatch for the libretro implementation. The audio goes through a
+// Backend, OpenAL by default.
 package audio
 
 import (
 	"log"
 	"path/filepath"
-	"time"
 	"unsafe"
 
+	"github.com/libretro/ludo/recording"
 	"github.com/libretro/ludo/settings"
 	"github.com/libretro/ludo/state"
 	"github.com/libretro/ludo/utils"
-	"golang.org/x/mobile/exp/audio/al"
 )
 
-const bufSize = 1024 * 8
+// deviceRate is the rate of the audio sent to the backends, the audio of the
+// cores is resampled to it
+const deviceRate = 48000
 
 var (
-	source     al.Source
-	buffers    []al.Buffer
-	rate       int32
-	numBuffers int32
-	tmpBuf     [bufSize]byte
-	tmpBufPtr  int32
-	resPtr     int32
+	backend Backend = null{}
+	rate    int32
+	res     *resampler
 )
 
 // Effects are sound effects
@@ -31,18 +29,37 @@ var Effects map[string]*Effect
 
 // SetVolume sets the audio volume
 func SetVolume(vol float32) {
-	source.SetGain(vol)
+	backend.SetVolume(vol)
 }
 
-// Init initializes the audio device
-func Init() {
-	err := al.OpenDevice()
-	if err != nil {
-		log.Println(err)
+// Init initializes the backend with the given name, see Backends
+func Init(name string) {
+	Effects = map[string]*Effect{}
+	SetBackend(name)
+}
+
+// SetBackend switches to the backend with the given name. The audio of the
+// running game continues on the new backend.
+func SetBackend(name string) {
+	backend.Close()
+
+	backend = newBackend(name)
+	if err := backend.Init(); err != nil {
+		log.Println("[Audio]:", err)
+		backend = null{}
 	}
 
-	Effects = map[string]*Effect{}
+	// Sound effects are played with OpenAL
+	if _, ok := backend.(*openAL); ok && len(Effects) == 0 {
+		loadEffects()
+	}
 
+	if res != nil {
+		Reconfigure(rate)
+	}
+}
+
+func loadEffects() {
 	assets := settings.Current.AssetsDirectory
 	paths, _ := filepath.Glob(assets + "/sounds/*.wav")
 	for _, path := range paths {
@@ -52,103 +69,80 @@ func Init() {
 	}
 }
 
-// Reconfigure initializes the audio package. It sets the number of buffers, the
-// volume and the source for the games.
+// Reconfigure prepares the backend for a new game. r is the sample rate of the
+// core.
 func Reconfigure(r int32) {
 	rate = r
-	numBuffers = 4
 
-	log.Printf("[OpenAL]: Using %v buffers of %v bytes.\n", numBuffers, bufSize)
+	log.Printf("[Audio]: Resampling from %v Hz to %v Hz.\n", rate, deviceRate)
 
-	source = al.GenSources(1)[0]
-	buffers = al.GenBuffers(int(numBuffers))
-	resPtr = numBuffers
-	tmpBufPtr = 0
-	tmpBuf = [bufSize]byte{}
+	backend.Close()
+	if err := backend.Open(rate); err != nil {
+		log.Println("[Audio]:", err)
+	}
+	res = newResampler()
 
-	source.SetGain(settings.Current.AudioVolume)
+	backend.SetVolume(settings.Current.AudioVolume)
 }
 
-func min(a, b int32) int32 {
-	if a < b {
-		return a
+// Close releases the resources of the game in the backend. It is called when
+// the game is unloaded.
+func Close() {
+	res = nil
+	if err := backend.Close(); err != nil {
+		log.Println("[Audio]:", err)
 	}
-	return b
 }
 
-func alUnqueueBuffers() bool {
-	val := source.BuffersProcessed()
+// Blocking returns true if the backend blocks when its buffers are full, so
+// it can pace the emulation
+func Blocking() bool {
+	_, capacity := backend.Free()
+	return capacity > 0
+}
 
-	if val <= 0 {
-		return false
+// ratio returns the resampling ratio for the current fill level of the backend
+func ratio() float64 {
+	if rate <= 0 {
+		return 1
 	}
-
-	source.UnqueueBuffers(buffers[resPtr:val]...)
-	resPtr += val
-	return true
+	free, capacity := backend.Free()
+	return rateControl(float64(deviceRate)/float64(rate), free, capacity)
 }
 
-func alGetBuffer() al.Buffer {
-	if resPtr == 0 {
-		for {
-			if alUnqueueBuffers() {
-				break
-			}
-			time.Sleep(time.Millisecond)
-		}
+// toSamples reinterprets raw audio bytes as samples
+func toSamples(buf []byte) []int16 {
+	if len(buf) < 2 {
+		return nil
 	}
-
-	resPtr--
-	return buffers[resPtr]
+	return (*[1 << 28]int16)(unsafe.Pointer(&buf[0]))[: len(buf)/2 : len(buf)/2]
 }
 
-func fillInternalBuf(buf []byte) int32 {
-	readSize := min(bufSize-tmpBufPtr, int32(len(buf)))
-	copy(tmpBuf[tmpBufPtr:], buf[:readSize])
-	tmpBufPtr += readSize
-	return readSize
+// toBytes reinterprets samples as raw audio bytes
+func toBytes(samples []int16) []byte {
+	if len(samples) == 0 {
+		return nil
+	}
+	return (*[1 << 29]byte)(unsafe.Pointer(&samples[0]))[: len(samples)*2 : len(samples)*2]
 }
 
 func write(buf []byte, size int32) int32 {
-	written := int32(0)
+	recording.Samples(buf[:size])
 
-	if state.FastForward {
+	if state.FastForward || res == nil {
 		return size
 	}
 
-	for size > 0 {
-
-		rc := fillInternalBuf(buf[written:])
-
-		written += rc
-		size -= rc
-
-		if tmpBufPtr != bufSize {
-			break
-		}
-
-		buffer := alGetBuffer()
-
-		buffer.BufferData(al.FormatStereo16, tmpBuf[:], rate)
-		tmpBufPtr = 0
-		source.QueueBuffers(buffer)
-
-		if source.State() != al.Playing {
-			al.PlaySources(source)
-		}
-	}
-
-	return written
+	out := toBytes(res.process(toSamples(buf[:size]), ratio()))
+	backend.Write(out)
+	return size
 }
 
 // Sample renders a single audio frame.
 // It is passed as a callback to the libretro implementation.
 func Sample(left int16, right int16) {
 	// simulate the kind of raw byte array that would be provided from C via SampleBatch.
-	// (effectively typecasting int16 array to byte array)
-	buf := []int16{left, right}
-	pi := (*[4]byte)(unsafe.Pointer(&buf[0]))
-	write((*pi)[:], 4)
+	write(toBytes([]int16{left, right}), 4)
 }
 
 // SampleBatch renders multiple audio frames in one go
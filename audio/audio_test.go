@@ -6,7 +6,9 @@ import (
 
 func Test_alUnqueueBuffers(t *testing.T) {
 	t.Run("Return false if no buffers were processed", func(t *testing.T) {
-		got := alUnqueueBuffers()
+		a := &openAL{}
+		a.Open(48000)
+		got := a.alUnqueueBuffers()
 		if got {
 			t.Errorf("alUnqueueBuffers() = %v, want %v", got, false)
 		}
@@ -21,7 +23,8 @@ func Test_Sample(t *testing.T) {
 }
 
 func Test_fillInternalBuf(t *testing.T) {
-	Reconfigure(48000)
+	a := &openAL{}
+	a.Open(48000)
 	type args struct {
 		buf  []byte
 		size int32
@@ -50,7 +53,7 @@ func Test_fillInternalBuf(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := fillInternalBuf(tt.args.buf[:tt.args.size]); got != tt.want {
+			if got := a.fillInternalBuf(tt.args.buf[:tt.args.size]); got != tt.want {
 				t.Errorf("fillInternalBuf() = %v, want %v", got, tt.want)
 			}
 		})
@@ -0,0 +1,50 @@
+package audio
+
+import (
+	"strings"
+)
+
+// Backend plays or stores the game audio, as stereo 16-bit samples at
+// deviceRate
+type Backend interface {
+	// Init is called once, when the backend is selected
+	Init() error
+	// Open prepares the backend for a new game
+	Open(rate int32) error
+	// Close releases what Open allocated
+	Close() error
+	// Write sends samples to the backend, it can block until there is room
+	Write(buf []byte)
+	// Free returns the free space and the capacity of the backend buffers, in
+	// bytes. It is used to adjust the resampling ratio.
+	Free() (free, capacity int32)
+	SetVolume(vol float32)
+}
+
+// Backends lists the names of the available backends, the first one is the
+// default
+var Backends = []string{"OpenAL", "Null", "WAV"}
+
+// newBackend returns the backend with the given name, or OpenAL if the name is
+// unknown
+func newBackend(name string) Backend {
+	switch strings.ToLower(name) {
+	case "null":
+		return &null{}
+	case "wav":
+		return &wavFile{}
+	default:
+		return &openAL{}
+	}
+}
+
+// null discards the game audio, it is meant for machines without a sound
+// device
+type null struct{}
+
+func (null) Init() error                  { return nil }
+func (null) Open(rate int32) error        { return nil }
+func (null) Close() error                 { return nil }
+func (null) Write(buf []byte)             {}
+func (null) Free() (free, capacity int32) { return 0, 0 }
+func (null) SetVolume(vol float32)        {}
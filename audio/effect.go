@@ -52,12 +52,18 @@ func LoadEffect(filename string) (*Effect, error) {
 
 // PlayEffect plays a sound effect
 func PlayEffect(e *Effect) {
+	if e == nil {
+		return
+	}
 	al.PlaySources(e.source)
 }
 
 // SetEffectsVolume sets the audio volume of sound effects
 func SetEffectsVolume(vol float32) {
 	for _, e := range Effects {
+		if e == nil {
+			continue
+		}
 		e.source.SetGain(vol)
 	}
 }
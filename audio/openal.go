@@ -0,0 +1,134 @@
+package audio
+
+import (
+	"log"
+	"time"
+
+	"golang.org/x/mobile/exp/audio/al"
+)
+
+const bufSize = 1024 * 8
+
+// openAL plays the game audio on the sound device. It is the default backend.
+type openAL struct {
+	source     al.Source
+	buffers    []al.Buffer
+	numBuffers int32
+	tmpBuf     [bufSize]byte
+	tmpBufPtr  int32
+	resPtr     int32
+}
+
+// Init opens the audio device
+func (a *openAL) Init() error {
+	return al.OpenDevice()
+}
+
+// Open sets the number of buffers and the source for the games
+func (a *openAL) Open(rate int32) error {
+	a.numBuffers = 4
+
+	log.Printf("[OpenAL]: Using %v buffers of %v bytes.\n", a.numBuffers, bufSize)
+
+	a.source = al.GenSources(1)[0]
+	a.buffers = al.GenBuffers(int(a.numBuffers))
+	a.resPtr = a.numBuffers
+	a.tmpBufPtr = 0
+	a.tmpBuf = [bufSize]byte{}
+	return nil
+}
+
+// Close releases the source and the buffers of the game
+func (a *openAL) Close() error {
+	if a.buffers == nil {
+		return nil
+	}
+	al.DeleteSources(a.source)
+	al.DeleteBuffers(a.buffers...)
+	a.buffers = nil
+	return nil
+}
+
+// SetVolume sets the gain of the source
+func (a *openAL) SetVolume(vol float32) {
+	a.source.SetGain(vol)
+}
+
+// Free returns the free space in the buffers. The buffers played since the
+// last call are reclaimed, without waiting.
+func (a *openAL) Free() (free, capacity int32) {
+	if a.resPtr < a.numBuffers {
+		a.alUnqueueBuffers()
+	}
+	free = a.resPtr*bufSize + bufSize - a.tmpBufPtr
+	capacity = (a.numBuffers + 1) * bufSize
+	return free, capacity
+}
+
+func min(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (a *openAL) alUnqueueBuffers() bool {
+	val := a.source.BuffersProcessed()
+
+	if val <= 0 {
+		return false
+	}
+
+	a.source.UnqueueBuffers(a.buffers[a.resPtr : a.resPtr+val]...)
+	a.resPtr += val
+	return true
+}
+
+func (a *openAL) alGetBuffer() al.Buffer {
+	if a.resPtr == 0 {
+		for {
+			if a.alUnqueueBuffers() {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	a.resPtr--
+	return a.buffers[a.resPtr]
+}
+
+func (a *openAL) fillInternalBuf(buf []byte) int32 {
+	readSize := min(bufSize-a.tmpBufPtr, int32(len(buf)))
+	copy(a.tmpBuf[a.tmpBufPtr:], buf[:readSize])
+	a.tmpBufPtr += readSize
+	return readSize
+}
+
+// Write queues audio to OpenAL, blocking when all the buffers are queued
+func (a *openAL) Write(buf []byte) {
+	written := int32(0)
+	size := int32(len(buf))
+
+	for size > 0 {
+
+		rc := a.fillInternalBuf(buf[written:])
+
+		written += rc
+		size -= rc
+
+		if a.tmpBufPtr != bufSize {
+			break
+		}
+
+		buffer := a.alGetBuffer()
+
+		buffer.BufferData(al.FormatStereo16, a.tmpBuf[:], deviceRate)
+		a.tmpBufPtr = 0
+		a.source.QueueBuffers(buffer)
+
+		if a.source.State() != al.Playing {
+			al.PlaySources(a.source)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package audio
+
+import "math"
+
+// maxRateDelta is how much the resampling ratio can be skewed to keep the
+// buffers half full, 0.5% is not audible
+const maxRateDelta = 0.005
+
+// resampler converts interleaved stereo frames from the rate of the core to the
+// rate of the device, using cubic interpolation
+type resampler struct {
+	frames [][2]float64 // input frames the next output frames depend on
+	pos    float64      // position of the next output frame in frames
+}
+
+func newResampler() *resampler {
+	// Start with a frame of silence so the first input frame has a predecessor
+	return &resampler{frames: make([][2]float64, 1), pos: 1}
+}
+
+// cubic interpolates between x1 and x2 with a Catmull-Rom spline, t is in [0,1)
+func cubic(x0, x1, x2, x3, t float64) float64 {
+	return x1 + 0.5*t*(x2-x0+t*(2*x0-5*x1+4*x2-x3+t*(3*(x1-x2)+x3-x0)))
+}
+
+func clamp(v float64) int16 {
+	return int16(math.Max(math.MinInt16, math.Min(math.MaxInt16, math.Round(v))))
+}
+
+// process resamples interleaved stereo samples. The ratio is the output rate
+// divided by the input rate. The last frames are kept for the next call, as
+// the interpolation needs the frames around each output frame.
+func (r *resampler) process(in []int16, ratio float64) []int16 {
+	for i := 0; i+1 < len(in); i += 2 {
+		r.frames = append(r.frames, [2]float64{float64(in[i]), float64(in[i+1])})
+	}
+
+	out := []int16{}
+	step := 1 / ratio
+	for int(r.pos)+2 < len(r.frames) {
+		i := int(r.pos)
+		t := r.pos - float64(i)
+		for c := 0; c < 2; c++ {
+			v := cubic(r.frames[i-1][c], r.frames[i][c], r.frames[i+1][c], r.frames[i+2][c], t)
+			out = append(out, clamp(v))
+		}
+		r.pos += step
+	}
+
+	drop := int(r.pos) - 1
+	if drop > len(r.frames) {
+		drop = len(r.frames)
+	}
+	r.frames = append(r.frames[:0], r.frames[drop:]...)
+	r.pos -= float64(drop)
+
+	return out
+}
+
+// rateControl skews the resampling ratio depending on how full the buffers
+// are. A buffer that drains gets more frames and a buffer that fills up gets
+// less, so the audio never underruns nor blocks the emulation for long.
+// See Dynamic Rate Control for Retro Game Emulators, Hans-Kristian Arntzen.
+func rateControl(ratio float64, free, capacity int32) float64 {
+	if capacity <= 0 {
+		return ratio
+	}
+	half := float64(capacity) / 2
+	direction := (float64(free) - half) / half
+	return ratio * (1 + maxRateDelta*direction)
+}
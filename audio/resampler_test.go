@@ -0,0 +1,107 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+// sine generates interleaved stereo frames of a sine wave
+func sine(freq, rate float64, from, n int) []int16 {
+	buf := make([]int16, 0, n*2)
+	for i := from; i < from+n; i++ {
+		v := int16(10000 * math.Sin(2*math.Pi*freq*float64(i)/rate))
+		buf = append(buf, v, v)
+	}
+	return buf
+}
+
+func Test_resampler(t *testing.T) {
+	t.Run("Keeps the frames with a ratio of 1", func(t *testing.T) {
+		r := newResampler()
+		in := []int16{}
+		for i := int16(0); i < 100; i++ {
+			in = append(in, i, -i)
+		}
+		got := r.process(in, 1)
+		if len(got) != 98*2 {
+			t.Fatalf("got = %v, want %v", len(got), 98*2)
+		}
+		for i := range got {
+			if got[i] != in[i] {
+				t.Fatalf("got = %v, want %v", got[i], in[i])
+			}
+		}
+	})
+
+	tests := []struct {
+		name  string
+		ratio float64
+	}{
+		{"Upsamples", 48000.0 / 32000},
+		{"Downsamples", 48000.0 / 96000},
+		{"Follows a small skew", 48000.0 / 44100 * (1 + maxRateDelta)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newResampler()
+			count := 0
+			for chunk := 0; chunk < 100; chunk++ {
+				count += len(r.process(sine(440, 48000, chunk*735, 735), tt.ratio)) / 2
+			}
+			want := int(73500 * tt.ratio)
+			if count < want-4 || count > want+4 {
+				t.Errorf("got = %v, want %v", count, want)
+			}
+		})
+	}
+
+	t.Run("Preserves a sine wave", func(t *testing.T) {
+		r := newResampler()
+		got := []int16{}
+		for chunk := 0; chunk < 10; chunk++ {
+			got = append(got, r.process(sine(440, 44100, chunk*512, 512), 48000.0/44100)...)
+		}
+		want := sine(440, 48000, 0, len(got)/2)
+		for i := range got {
+			if d := math.Abs(float64(got[i]) - float64(want[i])); d > 20 {
+				t.Fatalf("frame %d: got = %v, want %v", i/2, got[i], want[i])
+			}
+		}
+	})
+}
+
+func Test_clamp(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want int16
+	}{
+		{1.6, 2},
+		{-1.6, -2},
+		{40000, math.MaxInt16},
+		{-40000, math.MinInt16},
+	}
+	for _, tt := range tests {
+		if got := clamp(tt.in); got != tt.want {
+			t.Errorf("got = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func Test_rateControl(t *testing.T) {
+	tests := []struct {
+		name string
+		free int32
+		want float64
+	}{
+		{"Keeps the ratio when half full", 500, 1},
+		{"Speeds up when empty", 1000, 1 + maxRateDelta},
+		{"Slows down when full", 0, 1 - maxRateDelta},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rateControl(1, tt.free, 1000); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package audio
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/libretro/ludo/recording"
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/utils"
+)
+
+// wavFile records the game audio to a WAV file in the recordings directory,
+// one file per game
+type wavFile struct {
+	w *recording.WAV
+}
+
+func (w *wavFile) Init() error {
+	return nil
+}
+
+// Open creates the WAV file of the game
+func (w *wavFile) Open(rate int32) error {
+	w.Close()
+
+	dir := settings.Current.RecordingsDirectory
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, utils.DatedName(state.ContentPath())+".wav")
+	f, err := recording.CreateWAV(path, deviceRate)
+	if err != nil {
+		return err
+	}
+	w.w = f
+
+	log.Println("[Audio]: Recording to", path)
+	return nil
+}
+
+// Close writes the final header and closes the file
+func (w *wavFile) Close() error {
+	if w.w == nil {
+		return nil
+	}
+	defer func() { w.w = nil }()
+	return w.w.Close()
+}
+
+// Write appends samples to the file
+func (w *wavFile) Write(buf []byte) {
+	if w.w == nil {
+		return
+	}
+	if _, err := w.w.Write(buf); err != nil {
+		log.Println("[Audio]:", err)
+		w.Close()
+	}
+}
+
+func (w *wavFile) Free() (free, capacity int32) { return 0, 0 }
+func (w *wavFile) SetVolume(vol float32)        {}
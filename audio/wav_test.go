@@ -0,0 +1,66 @@
+package audio
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/state"
+	wav "github.com/youpy/go-wav"
+)
+
+func Test_wavFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ludo-audio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	settings.Current.RecordingsDirectory = dir
+	state.GamePath = "roms/Game.sfc"
+	defer func() { state.GamePath = "" }()
+
+	w := &wavFile{}
+	if err := w.Open(32000); err != nil {
+		t.Fatal(err)
+	}
+	w.Write(toBytes([]int16{1, -1, 2, -2, 3, -3}))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "Game@*.wav"))
+	if len(paths) != 1 {
+		t.Fatalf("got = %v, want 1 file", paths)
+	}
+	f, err := os.Open(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r := wav.NewReader(f)
+
+	t.Run("Writes the format of the samples", func(t *testing.T) {
+		format, err := r.Format()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if format.SampleRate != deviceRate || format.NumChannels != 2 || format.BitsPerSample != 16 {
+			t.Errorf("got = %+v, want stereo 16-bit at %v Hz", format, deviceRate)
+		}
+	})
+
+	t.Run("Writes the samples", func(t *testing.T) {
+		samples, err := r.ReadSamples()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(samples) != 3 {
+			t.Fatalf("got = %v, want %v", len(samples), 3)
+		}
+		if got := r.IntValue(samples[2], 1); got != -3 {
+			t.Errorf("got = %v, want %v", got, -3)
+		}
+	})
+}
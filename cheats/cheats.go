@@ -0,0 +1,175 @@
+// Package cheats loads cheats from RetroArch .cht files. Cheat codes like Game
+// Genie or Action Replay are passed to the core, while raw memory cheats are
+// applied by Ludo to the system RAM on each frame, which also works for cores
+// that don't implement cheats.
+package cheats
+
+import (
+	"math/bits"
+	"os"
+	"path/filepath"
+
+	"github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/utils"
+)
+
+// list of the cheats loaded for the current game
+var list []Cheat
+
+// List returns the cheats loaded for the current game
+func List() []Cheat {
+	return list
+}
+
+// Load looks for a .cht file for the game in the cheats directory. The file
+// can be at the root of the directory, or in a subdirectory named after the
+// core.
+func Load(gamePath string) error {
+	Reset()
+
+	name := utils.FileName(gamePath) + ".cht"
+	paths := []string{
+		filepath.Join(settings.Current.CheatsDirectory, state.Core.GetSystemInfo().LibraryName, name),
+		filepath.Join(settings.Current.CheatsDirectory, name),
+	}
+	for _, path := range paths {
+		l, err := parseFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		list = l
+		sync()
+		return nil
+	}
+
+	return nil
+}
+
+// parseFile parses a .cht file and closes it
+func parseFile(path string) ([]Cheat, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	return Parse(fd)
+}
+
+// Reset forgets the cheats of the current game
+func Reset() {
+	list = nil
+}
+
+// Toggle enables or disables a cheat
+func Toggle(i int) {
+	if i < 0 || i >= len(list) {
+		return
+	}
+	list[i].Enabled = !list[i].Enabled
+	sync()
+}
+
+// sync sends the core cheats to the core
+func sync() {
+	state.Core.CheatReset()
+	for i, c := range list {
+		if c.Handler == HandlerCore && c.Enabled && c.Code != "" {
+			state.Core.CheatSet(uint(i), true, c.Code)
+		}
+	}
+}
+
+// Frame applies the memory cheats. It has to be called after each frame run
+// by the core.
+func Frame() {
+	if len(list) == 0 {
+		return
+	}
+
+	size := int(state.Core.GetMemorySize(libretro.MemorySystemRAM))
+	data := state.Core.GetMemoryData(libretro.MemorySystemRAM)
+	if size == 0 || data == nil {
+		return
+	}
+
+	// this *[1 << 30]byte points to the same memory as data, allowing to
+	// write to the core memory directly
+	mem := (*[1 << 30]byte)(data)[:size:size]
+	for _, c := range list {
+		if c.Enabled && c.Handler == HandlerMemory {
+			apply(mem, c)
+		}
+	}
+}
+
+// apply writes a memory cheat to mem
+func apply(mem []byte, c Cheat) {
+	address := c.Address
+	value := c.Value
+	for r := 0; r < c.RepeatCount || r == 0; r++ {
+		write(mem, c, address, value)
+		address += c.RepeatAddToAddress
+		value += c.RepeatAddToValue
+	}
+}
+
+// write updates the value at a single address
+func write(mem []byte, c Cheat, address, value uint32) {
+	n := 1
+	switch c.Size {
+	case 4:
+		n = 2
+	case 5:
+		n = 4
+	}
+	if int(address)+n > len(mem) {
+		return
+	}
+
+	// Read the current value
+	var cur uint32
+	for i := 0; i < n; i++ {
+		shift := uint(i * 8)
+		if c.BigEndian {
+			shift = uint((n - 1 - i) * 8)
+		}
+		cur |= uint32(mem[int(address)+i]) << shift
+	}
+
+	// Sub byte cheats only affect the masked bits
+	var mask uint32
+	var shift uint
+	if c.Size < 3 {
+		mask = uint32(c.BitMask)
+		shift = uint(bits.TrailingZeros8(c.BitMask))
+		cur = (cur & mask) >> shift
+	}
+
+	switch c.Type {
+	case TypeSet:
+		cur = value
+	case TypeIncrease:
+		cur += value
+	case TypeDecrease:
+		cur -= value
+	default:
+		return
+	}
+
+	if c.Size < 3 {
+		cur = uint32(mem[address])&^mask | cur<<shift&mask
+	}
+
+	for i := 0; i < n; i++ {
+		shift := uint(i * 8)
+		if c.BigEndian {
+			shift = uint((n - 1 - i) * 8)
+		}
+		mem[int(address)+i] = byte(cur >> shift)
+	}
+}
@@ -0,0 +1,115 @@
+package cheats
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Parses core and memory cheats", func(t *testing.T) {
+		cht := `cheats = 2
+
+cheat0_desc = "Infinite Lives"
+cheat0_code = "SZKZGZVG"
+cheat0_enable = true
+
+cheat1_desc = "Max Money"
+cheat1_code = ""
+cheat1_enable = false
+cheat1_handler = "1"
+cheat1_address = "4660"
+cheat1_value = "0x270f"
+cheat1_memory_search_size = "4"
+cheat1_big_endian = "true"
+`
+		got, err := Parse(strings.NewReader(cht))
+		if err != nil {
+			t.Errorf("Parse() = %v, want %v", err, nil)
+		}
+		want := []Cheat{
+			{Desc: "Infinite Lives", Code: "SZKZGZVG", Enabled: true, Handler: HandlerCore,
+				Type: TypeSet, Size: 3, BitMask: 0xff, RepeatCount: 1, RepeatAddToAddress: 1},
+			{Desc: "Max Money", Handler: HandlerMemory, Address: 4660, Value: 9999,
+				Type: TypeSet, Size: 4, BigEndian: true, BitMask: 0xff, RepeatCount: 1, RepeatAddToAddress: 1},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Caps the repeat count", func(t *testing.T) {
+		cht := `cheats = 1
+
+cheat0_handler = "1"
+cheat0_repeat_count = "1000000000"
+`
+		got, err := Parse(strings.NewReader(cht))
+		if err != nil {
+			t.Errorf("Parse() = %v, want %v", err, nil)
+		}
+		if len(got) != 1 || got[0].RepeatCount != maxRepeatCount {
+			t.Errorf("got = %v, want a repeat count of %v", got, maxRepeatCount)
+		}
+	})
+
+	t.Run("Detects a missing cheat count", func(t *testing.T) {
+		_, err := Parse(strings.NewReader(`cheat0_desc = "Infinite Lives"`))
+		if err == nil {
+			t.Errorf("Parse() = %v, want an error", err)
+		}
+	})
+}
+
+func Test_apply(t *testing.T) {
+	tests := []struct {
+		name  string
+		cheat Cheat
+		want  []byte
+	}{
+		{
+			name:  "Sets a byte",
+			cheat: Cheat{Address: 1, Value: 0x63, Type: TypeSet, Size: 3},
+			want:  []byte{0x11, 0x63, 0x33, 0x44},
+		},
+		{
+			name:  "Sets a little endian word",
+			cheat: Cheat{Address: 1, Value: 0xabcd, Type: TypeSet, Size: 4},
+			want:  []byte{0x11, 0xcd, 0xab, 0x44},
+		},
+		{
+			name:  "Sets a big endian long",
+			cheat: Cheat{Value: 0x01020304, Type: TypeSet, Size: 5, BigEndian: true},
+			want:  []byte{0x01, 0x02, 0x03, 0x04},
+		},
+		{
+			name:  "Increases a byte",
+			cheat: Cheat{Address: 3, Value: 1, Type: TypeIncrease, Size: 3},
+			want:  []byte{0x11, 0x22, 0x33, 0x45},
+		},
+		{
+			name:  "Sets the masked bits only",
+			cheat: Cheat{Address: 0, Value: 0xf, Type: TypeSet, Size: 2, BitMask: 0xf0},
+			want:  []byte{0xf1, 0x22, 0x33, 0x44},
+		},
+		{
+			name:  "Repeats a cheat",
+			cheat: Cheat{Value: 1, Type: TypeSet, Size: 3, RepeatCount: 3, RepeatAddToValue: 1, RepeatAddToAddress: 1},
+			want:  []byte{0x01, 0x02, 0x03, 0x44},
+		},
+		{
+			name:  "Ignores out of bounds addresses",
+			cheat: Cheat{Address: 3, Value: 0xffff, Type: TypeSet, Size: 4},
+			want:  []byte{0x11, 0x22, 0x33, 0x44},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []byte{0x11, 0x22, 0x33, 0x44}
+			apply(got, tt.cheat)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
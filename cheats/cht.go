@@ -0,0 +1,115 @@
+package cheats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Cheat handlers
+const (
+	// HandlerCore cheats are codes passed to the core with retro_cheat_set
+	HandlerCore = 0
+	// HandlerMemory cheats are applied by Ludo to the system RAM of the core
+	HandlerMemory = 1
+)
+
+// Memory cheat types
+const (
+	TypeDisabled = 0
+	TypeSet      = 1
+	TypeIncrease = 2
+	TypeDecrease = 3
+)
+
+// maxRepeatCount is the highest repeat count accepted, the limit of the
+// RetroArch cheat editor. Repeats are applied every frame.
+const maxRepeatCount = 2048
+
+// Cheat is a cheat as described in a RetroArch .cht file
+type Cheat struct {
+	Desc    string
+	Code    string
+	Enabled bool
+	Handler int
+
+	// The following fields are only used by memory cheats
+	Address            uint32
+	Value              uint32
+	Type               int
+	Size               int // 0: 1 bit, 1: 2 bits, 2: 4 bits, 3: 8 bits, 4: 16 bits, 5: 32 bits
+	BigEndian          bool
+	BitMask            uint8 // bits of the byte affected by 1, 2 and 4 bits cheats
+	RepeatCount        int
+	RepeatAddToValue   uint32
+	RepeatAddToAddress uint32
+}
+
+// Parse reads a RetroArch .cht file
+func Parse(r io.Reader) ([]Cheat, error) {
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		value := strings.TrimSpace(kv[1])
+		values[key] = strings.Trim(value, `"`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	count, err := strconv.Atoi(values["cheats"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cheat count: %v", err)
+	}
+
+	list := []Cheat{}
+	for i := 0; i < count; i++ {
+		get := func(name string) string {
+			return values[fmt.Sprintf("cheat%d_%s", i, name)]
+		}
+		num := func(name string, def uint64) uint64 {
+			n, err := strconv.ParseUint(get(name), 0, 32)
+			if err != nil {
+				return def
+			}
+			return n
+		}
+
+		c := Cheat{
+			Desc:               get("desc"),
+			Code:               get("code"),
+			Enabled:            get("enable") == "true",
+			Handler:            int(num("handler", HandlerCore)),
+			Address:            uint32(num("address", 0)),
+			Value:              uint32(num("value", 0)),
+			Type:               int(num("cheat_type", TypeSet)),
+			Size:               int(num("memory_search_size", 3)),
+			BigEndian:          get("big_endian") == "true",
+			BitMask:            uint8(num("address_bit_position", 0xff)),
+			RepeatCount:        int(num("repeat_count", 1)),
+			RepeatAddToValue:   uint32(num("repeat_add_to_value", 0)),
+			RepeatAddToAddress: uint32(num("repeat_add_to_address", 1)),
+		}
+		if c.RepeatCount > maxRepeatCount {
+			c.RepeatCount = maxRepeatCount
+		}
+		if c.Desc == "" {
+			c.Desc = fmt.Sprintf("Cheat %d", i+1)
+		}
+		list = append(list, c)
+	}
+
+	return list, nil
+}
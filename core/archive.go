@@ -0,0 +1,168 @@
+package core
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/utils"
+	"github.com/libretro/ludo/vfs"
+)
+
+// tmpDirs are the directories the archives of the current game were extracted
+// to. They are removed when the game is unloaded.
+var tmpDirs []string
+
+// isArchive tells if a file is an archive that games can be loaded from
+func isArchive(path string) bool {
+	return utils.StringInSlice(strings.ToLower(filepath.Ext(path)), utils.ArchiveExts)
+}
+
+// romCandidates keeps the files of an archive that have one of the valid
+// extensions of the core, like "sfc|smc". All the files are kept if the core
+// doesn't list its extensions.
+func romCandidates(files []string, validExtensions string) []string {
+	if validExtensions == "" {
+		return files
+	}
+	exts := strings.Split(strings.ToLower(validExtensions), "|")
+	roms := []string{}
+	for _, f := range files {
+		ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(f)), ".")
+		if utils.StringInSlice(ext, exts) {
+			roms = append(roms, f)
+		}
+	}
+	return roms
+}
+
+// ArchiveRoms lists the roms in an archive that the current core can load.
+// It returns nil if the path is not an archive, or if the core opens archives
+// itself.
+func ArchiveRoms(path string) ([]string, error) {
+	si := state.Core.GetSystemInfo()
+	if !isArchive(path) || si.BlockExtract {
+		return nil, nil
+	}
+	files, err := archiveFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	return romCandidates(files, si.ValidExtensions), nil
+}
+
+// archiveFiles lists the regular files of an archive
+func archiveFiles(archive string) ([]string, error) {
+	if strings.EqualFold(filepath.Ext(archive), ".7z") {
+		return vfs.SevenZip{Archive: archive}.Files()
+	}
+	return vfs.Zip{Archive: archive}.Files()
+}
+
+// safeJoin joins the name of an archive entry to the extraction directory.
+// Names that would escape the directory, like ../../.bashrc, are refused.
+func safeJoin(dir, name string) (string, error) {
+	target := filepath.Join(dir, filepath.FromSlash(name))
+	if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal file path in archive: %s", name)
+	}
+	return target, nil
+}
+
+// extractArchive extracts an archive to a new temporary directory, removed when
+// the game is unloaded
+func extractArchive(archive string) (string, error) {
+	dir, err := ioutil.TempDir("", "ludo-")
+	if err != nil {
+		return "", err
+	}
+	tmpDirs = append(tmpDirs, dir)
+
+	if strings.EqualFold(filepath.Ext(archive), ".7z") {
+		err = extract7z(archive, dir)
+	} else {
+		err = extractZip(archive, dir)
+	}
+	return dir, err
+}
+
+// removeTmpDirs removes the directories the archives were extracted to
+func removeTmpDirs() {
+	for _, dir := range tmpDirs {
+		os.RemoveAll(dir)
+	}
+	tmpDirs = nil
+}
+
+// extractZip extracts all the files of a zip archive to dir
+func extractZip(archive, dir string) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, cf := range r.File {
+		path, err := safeJoin(dir, cf.Name)
+		if err != nil {
+			return err
+		}
+
+		if cf.FileInfo().IsDir() {
+			os.MkdirAll(path, os.ModePerm)
+			continue
+		}
+
+		if err = os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+
+		if err := extractZipFile(cf, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// extractZipFile writes a file of a zip archive to path
+func extractZipFile(cf *zip.File, path string) error {
+	rc, err := cf.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	outFile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, cf.Mode())
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	_, err = io.Copy(outFile, rc)
+	return err
+}
+
+// extract7z extracts all the files of a 7z archive to dir
+func extract7z(archive, dir string) error {
+	files, err := vfs.SevenZip{Archive: archive}.Files()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if _, err := safeJoin(dir, f); err != nil {
+			return err
+		}
+	}
+	bin, err := vfs.SevenZipCommand()
+	if err != nil {
+		return err
+	}
+	return exec.Command(bin, "x", "-y", "-o"+dir, "--", archive).Run()
+}
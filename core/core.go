@@ -4,21 +4,31 @@
 package core
 
 import (
-	"archive/zip"
 	"errors"
-	"io"
-	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/libretro/ludo/achievements"
 	"github.com/libretro/ludo/audio"
+	"github.com/libretro/ludo/cheats"
+	"github.com/libretro/ludo/history"
 	"github.com/libretro/ludo/input"
 	"github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/movie"
+	ntf "github.com/libretro/ludo/notifications"
 	"github.com/libretro/ludo/options"
 	"github.com/libretro/ludo/patch"
+	"github.com/libretro/ludo/recording"
+	"github.com/libretro/ludo/rewind"
 	"github.com/libretro/ludo/savefiles"
+	"github.com/libretro/ludo/savestates"
+	"github.com/libretro/ludo/scheduler"
+	"github.com/libretro/ludo/settings"
 	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/utils"
+	"github.com/libretro/ludo/vfs"
 	"github.com/libretro/ludo/video"
 )
 
@@ -71,59 +81,9 @@ func Load(sofile string) error {
 	return nil
 }
 
-// unzipGame unzips a rom to tmpdir and returns the path and size of the extracted ROM.
-// In case the zip contains more than one file, they are all extracted and the
-// first one is passed to the libretro core.
-func unzipGame(filename string) (string, int64, error) {
-	r, err := zip.OpenReader(filename)
-	if err != nil {
-		return "", 0, err
-	}
-	defer r.Close()
-
-	var mainPath string
-	var mainSize int64
-	for i, cf := range r.File {
-		size := int64(cf.UncompressedSize)
-		rc, err := cf.Open()
-		if err != nil {
-			return "", 0, err
-		}
-
-		path := filepath.Join(os.TempDir(), cf.Name)
-
-		if cf.FileInfo().IsDir() {
-			os.MkdirAll(path, os.ModePerm)
-			continue
-		}
-
-		if err = os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
-			return "", 0, err
-		}
-
-		outFile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, cf.Mode())
-		if err != nil {
-			return "", 0, err
-		}
-
-		if _, err = io.Copy(outFile, rc); err != nil {
-			return "", 0, err
-		}
-		outFile.Close()
-		rc.Close()
-
-		if i == 0 {
-			mainPath = path
-			mainSize = size
-		}
-	}
-
-	return mainPath, mainSize, nil
-}
-
 // LoadGame loads a game. A core has to be loaded first.
 func LoadGame(gamePath string) error {
-	if _, err := os.Stat(gamePath); os.IsNotExist(err) {
+	if _, err := os.Stat(GameFile(gamePath)); os.IsNotExist(err) {
 		return err
 	}
 
@@ -135,23 +95,30 @@ func LoadGame(gamePath string) error {
 
 	si := state.Core.GetSystemInfo()
 
-	gi, err := getGameInfo(gamePath, si.BlockExtract)
+	gi, err := getGameInfo(gamePath, si.ValidExtensions, si.NeedFullpath && !state.Core.UsesVFS, si.BlockExtract)
 	if err != nil {
 		return err
 	}
 
 	if !si.NeedFullpath {
-		bytes, err := ioutil.ReadFile(gi.Path)
+		bytes, err := vfs.ReadFile(gi.Path)
 		if err != nil {
 			return err
 		}
 
-		if patched, _ := patch.Try(gamePath, bytes); patched != nil {
-			gi.Size = int64(len(*patched))
-			gi.SetData(*patched)
-		} else {
-			gi.SetData(bytes)
+		patched, err := patch.Try(gamePath, bytes)
+		if err != nil {
+			ntf.DisplayAndLog(ntf.Error, "Patch", "Could not apply %s. Loading the unpatched game.", err)
+		}
+		if patched != nil {
+			bytes = *patched
+			gi.Size = int64(len(bytes))
+			ntf.DisplayAndLog(ntf.Info, "Patch", "Applied %d patch(es).", len(patch.Applied()))
 		}
+		gi.SetData(bytes)
+		savestates.SetGameData(bytes)
+	} else {
+		savestates.SetGamePath(gi.Path)
 	}
 
 	ok := state.Core.LoadGame(*gi)
@@ -160,35 +127,154 @@ func LoadGame(gamePath string) error {
 		return errors.New("failed to load the game")
 	}
 
+	startGame(si, gamePath)
+
+	return nil
+}
+
+// LoadGameSpecial loads a game of a subsystem, like a Super Game Boy game. The
+// paths are in the order of the roms of the subsystem, an empty path leaves an
+// optional slot empty. A core has to be loaded first.
+func LoadGameSpecial(sub libretro.SubsystemInfo, paths []string) error {
+	if len(paths) != len(sub.Roms) {
+		return errors.New("wrong number of roms")
+	}
+
+	UnloadGame()
+
+	gis := make([]libretro.GameInfo, len(sub.Roms))
+	for i, rom := range sub.Roms {
+		if paths[i] == "" {
+			if rom.Required {
+				return errors.New(rom.Desc + " is required")
+			}
+			continue
+		}
+		if _, err := os.Stat(GameFile(paths[i])); os.IsNotExist(err) {
+			return err
+		}
+
+		gi, err := getGameInfo(paths[i], rom.ValidExtensions, rom.NeedFullpath && !state.Core.UsesVFS, rom.BlockExtract)
+		if err != nil {
+			return err
+		}
+		if !rom.NeedFullpath {
+			bytes, err := vfs.ReadFile(gi.Path)
+			if err != nil {
+				return err
+			}
+			gi.SetData(bytes)
+		}
+		gis[i] = *gi
+	}
+
+	ok := state.Core.LoadGameSpecial(sub.ID, gis)
+	if !ok {
+		state.CoreRunning = false
+		return errors.New("failed to load the game")
+	}
+
+	savefiles.SetSubsystem(&sub, paths)
+	state.ContentName = subsystemContentName(sub, paths)
+	startGame(state.Core.GetSystemInfo(), "")
+
+	return nil
+}
+
+// subsystemContentName names the files of a subsystem game after all its roms,
+// like STBIOS+Poi Poi Ninja.sufami, so that two games sharing a BIOS don't
+// share their savestates
+func subsystemContentName(sub libretro.SubsystemInfo, paths []string) string {
+	names := []string{}
+	dir := ""
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if dir == "" {
+			dir = filepath.Dir(GameFile(path))
+		}
+		names = append(names, utils.FileName(path))
+	}
+	ext := sub.Ident
+	if ext == "" {
+		ext = "subsystem"
+	}
+	return filepath.Join(dir, strings.Join(names, "+")+"."+ext)
+}
+
+// LoadNoGame starts a core that supports running without a game, like a game
+// engine that embeds its data. A core has to be loaded first.
+func LoadNoGame() error {
+	if !state.Core.SupportNoGame {
+		return errors.New("the core needs a game")
+	}
+
+	UnloadGame()
+
+	ok := state.Core.LoadNoGame()
+	if !ok {
+		state.CoreRunning = false
+		return errors.New("failed to start the core")
+	}
+
+	startGame(state.Core.GetSystemInfo(), "")
+
+	return nil
+}
+
+// startGame sets up the frontend for the game that the core just loaded
+func startGame(si libretro.SystemInfo, gamePath string) {
 	avi := state.Core.GetSystemAVInfo()
 
 	vid.Geom = avi.Geometry
+	vid.CoreName = si.LibraryName
+	scheduler.SetFPS(avi.Timing.FPS)
+
+	// Hardware rendered cores draw to a framebuffer that has to exist before
+	// the context is reset
+	if state.Core.HWRenderCallback != nil {
+		vid.InitFramebuffer(avi.Geometry.MaxWidth, avi.Geometry.MaxHeight)
+		state.Core.HWRenderCallback.ContextReset()
+	}
 
 	// Append the library name to the window title.
 	if len(si.LibraryName) > 0 {
 		vid.SetTitle("Ludo - " + si.LibraryName)
 	}
 
-	input.Init(vid)
-	audio.Reconfigure(int32(avi.Timing.SampleRate))
+	// The content path names the files of the game, like the WAV recordings
+	// opened by the audio backend
+	state.GamePath = gamePath
+
+	if !state.Headless {
+		input.Init(vid)
+		if err := input.LoadBinds(si.LibraryName, gamePath); err != nil {
+			ntf.DisplayAndLog(ntf.Error, "Input", "Could not load remapping: %s", err)
+		}
+		audio.Reconfigure(int32(avi.Timing.SampleRate))
+	}
 	if state.Core.AudioCallback != nil {
 		state.Core.AudioCallback.SetState(true)
 	}
 
 	state.CoreRunning = true
 	state.FastForward = false
-	state.GamePath = gamePath
+	rewind.Reset()
 
-	state.Core.SetControllerPortDevice(0, libretro.DeviceJoypad)
-	state.Core.SetControllerPortDevice(1, libretro.DeviceJoypad)
-	state.Core.SetControllerPortDevice(2, libretro.DeviceJoypad)
-	state.Core.SetControllerPortDevice(3, libretro.DeviceJoypad)
-	state.Core.SetControllerPortDevice(4, libretro.DeviceJoypad)
+	for port := uint(0); port < input.MaxPlayers; port++ {
+		state.Core.SetControllerPortDevice(port, input.PortDevice(port))
+	}
 
-	log.Println("[Core]: Game loaded: " + gamePath)
+	log.Println("[Core]: Game loaded: " + state.ContentPath())
 	savefiles.LoadSRAM()
 
-	return nil
+	if err := cheats.Load(state.ContentPath()); err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Cheats", err.Error())
+	}
+	if err := achievements.Load(state.ContentPath()); err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Achievements", err.Error())
+	}
 }
 
 // Unload unloads a libretro core
@@ -205,33 +291,126 @@ func Unload() {
 // UnloadGame unloads a game.
 func UnloadGame() {
 	if state.CoreRunning {
+		movie.Stop()
+		if err := recording.Stop(); err != nil {
+			ntf.DisplayAndLog(ntf.Error, "Recording", err.Error())
+		}
+		input.StopRumble()
+		if settings.Current.AutoSavestate && !state.Headless {
+			autoSavestate()
+		}
+		cheats.Reset()
+		achievements.Reset()
+		savestates.Reset()
+		patch.Reset()
 		savefiles.SaveSRAM()
+		savefiles.SetSubsystem(nil, nil)
+		if state.Core.HWRenderCallback != nil {
+			state.Core.HWRenderCallback.ContextDestroy()
+			state.Core.HWRenderCallback = nil
+		}
 		state.Core.UnloadGame()
+		if !state.Headless {
+			audio.Close()
+		}
 		state.GamePath = ""
+		state.ContentName = ""
 		state.CoreRunning = false
+		state.GameFocus = false
+		rewind.Reset()
 		vid.ResetPitch()
 		vid.ResetRot()
+		vid.ResetHWRender()
+		if !state.Headless {
+			if err := input.LoadBinds("", ""); err != nil {
+				ntf.DisplayAndLog(ntf.Error, "Input", "Could not load remapping: %s", err)
+			}
+		}
 	}
+	removeTmpDirs()
+	vfs.CloseArchives()
 }
 
-// getGameInfo opens a rom and return the libretro.GameInfo needed to launch it
-func getGameInfo(filename string, blockExtract bool) (*libretro.GameInfo, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, err
+// autoSavestate saves the state of the running game and records it in the
+// history, so the game can be resumed later
+func autoSavestate() {
+	name := savestates.AutoName()
+	if err := vid.TakeScreenshot(name); err != nil {
+		log.Println("[Core]: Could not take screenshot:", err)
+	}
+	if err := savestates.Save(name); err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Core", "Could not save state: %s", err)
+		return
 	}
+	// Subsystem games are not in the history
+	if state.ContentName != "" {
+		return
+	}
+	if err := history.SetSavestate(state.GamePath, state.CorePath, savestates.Path(name)); err != nil {
+		log.Println("[Core]: Could not save history:", err)
+	}
+}
 
-	fi, err := file.Stat()
-	if err != nil {
-		return nil, err
+// GameFile returns the path of a game on the file system. For a file inside an
+// archive, it is the path of the archive.
+func GameFile(path string) string {
+	if archive, _ := utils.SplitArchivePath(path); archive != "" {
+		return archive
+	}
+	return path
+}
+
+// getGameInfo opens a rom and return the libretro.GameInfo needed to launch it.
+// The rom of an archive is the file of the path, like game.zip#game.sfc, or the
+// first file that has one of the valid extensions. Roms in archives are read
+// through the VFS, or extracted to a temporary directory if the core needs a
+// file on disk.
+func getGameInfo(path, validExtensions string, needFile, blockExtract bool) (*libretro.GameInfo, error) {
+	archive, entry := utils.SplitArchivePath(path)
+	if archive == "" && isArchive(path) {
+		archive = path
 	}
 
-	if filepath.Ext(filename) == ".zip" && !blockExtract {
-		path, size, err := unzipGame(filename)
+	if archive == "" || blockExtract {
+		fi, err := os.Stat(GameFile(path))
 		if err != nil {
 			return nil, err
 		}
-		return &libretro.GameInfo{Path: path, Size: size}, nil
+		return &libretro.GameInfo{Path: GameFile(path), Size: fi.Size()}, nil
+	}
+
+	if entry == "" {
+		files, err := archiveFiles(archive)
+		if err != nil {
+			return nil, err
+		}
+		roms := romCandidates(files, validExtensions)
+		if len(roms) == 0 {
+			return nil, errors.New("no game found in the archive")
+		}
+		entry = roms[0]
+	}
+
+	if !needFile {
+		path := archive + vfs.Separator + entry
+		fi, err := vfs.FS{}.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		return &libretro.GameInfo{Path: path, Size: fi.Size()}, nil
+	}
+
+	dir, err := extractArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+	path, err = safeJoin(dir, entry)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
 	}
-	return &libretro.GameInfo{Path: filename, Size: fi.Size()}, nil
+	return &libretro.GameInfo{Path: path, Size: fi.Size()}, nil
 }
@@ -64,8 +64,10 @@ func Test_coreLoad(t *testing.T) {
 
 func Test_getGameInfo(t *testing.T) {
 	type args struct {
-		filename     string
-		blockExtract bool
+		filename        string
+		validExtensions string
+		needFile        bool
+		blockExtract    bool
 	}
 	tests := []struct {
 		name    string
@@ -83,14 +85,20 @@ func Test_getGameInfo(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "Returns the right path and size for a zipped ROM",
+			name: "Returns the path inside the archive for a zipped ROM",
 			args: args{filename: "testdata/Polar Rescue (USA).zip", blockExtract: false},
 			want: &libretro.GameInfo{
-				Path: filepath.Join(os.TempDir(), "Polar Rescue (USA).vec"),
+				Path: "testdata/Polar Rescue (USA).zip#Polar Rescue (USA).vec",
 				Size: 8192,
 			},
 			wantErr: false,
 		},
+		{
+			name:    "Returns an error when the archive has no valid ROM",
+			args:    args{filename: "testdata/Polar Rescue (USA).zip", validExtensions: "sfc|smc"},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Returns the right path and size for a zipped ROM with blockExtract",
 			args: args{filename: "testdata/Polar Rescue (USA).zip", blockExtract: true},
@@ -127,7 +135,7 @@ func Test_getGameInfo(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getGameInfo(tt.args.filename, tt.args.blockExtract)
+			got, err := getGameInfo(tt.args.filename, tt.args.validExtensions, tt.args.needFile, tt.args.blockExtract)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getGameInfo() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -139,51 +147,106 @@ func Test_getGameInfo(t *testing.T) {
 	}
 }
 
-func Test_unzipGame(t *testing.T) {
-	type args struct {
-		filename string
+func Test_getGameInfo_extract(t *testing.T) {
+	defer removeTmpDirs()
+
+	got, err := getGameInfo("testdata/Polar Rescue (USA).zip", "bin|vec", true, false)
+	if err != nil {
+		t.Fatal(err)
 	}
+
+	t.Run("Extracts to a temporary directory", func(t *testing.T) {
+		want := "Polar Rescue (USA).vec"
+		if filepath.Base(got.Path) != want {
+			t.Errorf("got = %v, want %v", filepath.Base(got.Path), want)
+		}
+		if got.Size != 8192 {
+			t.Errorf("got = %v, want %v", got.Size, 8192)
+		}
+	})
+
+	t.Run("Removes the temporary directory", func(t *testing.T) {
+		removeTmpDirs()
+		if _, err := os.Stat(got.Path); !os.IsNotExist(err) {
+			t.Errorf("got = %v, want %v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func Test_romCandidates(t *testing.T) {
+	files := []string{"readme.txt", "Game (Disc 1).cue", "Game (Disc 1).bin", "Game (Disc 2).CUE"}
 	tests := []struct {
-		name    string
-		args    args
-		want    string
-		want1   int64
-		wantErr bool
+		name            string
+		validExtensions string
+		want            []string
+	}{
+		{"Keeps the files with a valid extension", "cue|chd", []string{"Game (Disc 1).cue", "Game (Disc 2).CUE"}},
+		{"Keeps all the files without extensions", "", files},
+		{"Returns nothing without matches", "sfc", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := romCandidates(files, tt.validExtensions); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_subsystemContentName(t *testing.T) {
+	sufami := libretro.SubsystemInfo{Ident: "sufami"}
+	tests := []struct {
+		name  string
+		paths []string
+		want  string
 	}{
 		{
-			name:    "Should unzip to the right path",
-			args:    args{filename: "testdata/Polar Rescue (USA).zip"},
-			want:    filepath.Join(os.TempDir(), "Polar Rescue (USA).vec"),
-			want1:   8192,
-			wantErr: false,
+			"Names the game after all its roms",
+			[]string{"/roms/STBIOS.bin", "/roms/Poi Poi Ninja.st", "/roms/SD Gundam.st"},
+			filepath.Join("/roms", "STBIOS+Poi Poi Ninja+SD Gundam.sufami"),
 		},
 		{
-			name:    "Returns an error if the file is not a zip",
-			args:    args{filename: "testdata/Polar Rescue (USA).vec"},
-			want:    "",
-			want1:   0,
-			wantErr: true,
+			"Skips the empty slots",
+			[]string{"/roms/STBIOS.bin", "/roms/Poi Poi Ninja v1.1.st", ""},
+			filepath.Join("/roms", "STBIOS+Poi Poi Ninja v1.1.sufami"),
 		},
 		{
-			name:    "Returns an error if the file doesn't exists",
-			args:    args{filename: "testdata/Polar Rescue (USA)2.zip"},
-			want:    "",
-			want1:   0,
-			wantErr: true,
+			"Names roms in archives after the rom",
+			[]string{"/bios/STBIOS.bin", "/roms/carts.zip#Poi Poi Ninja.st"},
+			filepath.Join("/bios", "STBIOS+Poi Poi Ninja.sufami"),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, got1, err := unzipGame(tt.args.filename)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("unzipGame() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
+			got := subsystemContentName(sufami, tt.paths)
 			if got != tt.want {
-				t.Errorf("unzipGame() got = %v, want %v", got, tt.want)
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+			if name := utils.FileName(got); name != strings.TrimSuffix(filepath.Base(tt.want), ".sufami") {
+				t.Errorf("got = %v, want %v", name, strings.TrimSuffix(filepath.Base(tt.want), ".sufami"))
+			}
+		})
+	}
+}
+
+func Test_safeJoin(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"game.sfc", false},
+		{"dir/game.sfc", false},
+		{"../game.sfc", true},
+		{"dir/../../game.sfc", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := safeJoin("/tmp/ludo-1", tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got = %v, want error %v", err, tt.wantErr)
 			}
-			if got1 != tt.want1 {
-				t.Errorf("unzipGame() got1 = %v, want %v", got1, tt.want1)
+			if err == nil && !strings.HasPrefix(got, filepath.FromSlash("/tmp/ludo-1/")) {
+				t.Errorf("got = %v, want a path in %v", got, "/tmp/ludo-1")
 			}
 		})
 	}
@@ -7,10 +7,14 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/libretro/ludo/achievements"
+	"github.com/libretro/ludo/input"
 	"github.com/libretro/ludo/libretro"
 	"github.com/libretro/ludo/options"
+	"github.com/libretro/ludo/scheduler"
 	"github.com/libretro/ludo/settings"
 	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/vfs"
 )
 
 var logLevels = map[uint32]string{
@@ -45,6 +49,15 @@ func environmentSetPixelFormat(data unsafe.Pointer) bool {
 	return vid.SetPixelFormat(format)
 }
 
+func environmentSetHWRender(data unsafe.Pointer) bool {
+	state.Core.SetHWRenderCallback(data, vid.CurrentFramebuffer, vid.ProcAddress)
+	if !vid.SetHWRender(state.Core.HWRenderCallback) {
+		state.Core.HWRenderCallback = nil
+		return false
+	}
+	return true
+}
+
 func environmentGetUsername(data unsafe.Pointer) bool {
 	currentUser, err := user.Current()
 	if err != nil {
@@ -143,10 +156,16 @@ func environment(cmd uint32, data unsafe.Pointer) bool {
 		state.Core.SetFrameTimeCallback(data)
 	case libretro.EnvironmentSetAudioCallback:
 		state.Core.SetAudioCallback(data)
+	case libretro.EnvironmentGetRumbleInterface:
+		state.Core.BindRumbleInterface(data, input.SetRumbleState)
+	case libretro.EnvironmentSetKeyboardCallback:
+		state.Core.SetKeyboardCallback(data)
 	case libretro.EnvironmentGetCanDupe:
 		libretro.SetBool(data, true)
 	case libretro.EnvironmentSetPixelFormat:
 		return environmentSetPixelFormat(data)
+	case libretro.EnvironmentSetHWRender:
+		return environmentSetHWRender(data)
 	case libretro.EnvironmentGetSystemDirectory:
 		return environmentGetSystemDirectory(data)
 	case libretro.EnvironmentGetSaveDirectory:
@@ -171,6 +190,7 @@ func environment(cmd uint32, data unsafe.Pointer) bool {
 	case libretro.EnvironmentSetSystemAVInfo:
 		avi := libretro.GetSystemAVInfo(data)
 		vid.Geom = avi.Geometry
+		scheduler.SetFPS(avi.Timing.FPS)
 	case libretro.EnvironmentGetFastforwarding:
 		libretro.SetBool(data, state.FastForward)
 	case libretro.EnvironmentGetLanguage:
@@ -179,6 +199,19 @@ func environment(cmd uint32, data unsafe.Pointer) bool {
 		libretro.SetUint(data, 0)
 	case libretro.EnvironmentSetDiskControlInterface:
 		state.Core.SetDiskControlCallback(data)
+	case libretro.EnvironmentSetMemoryMaps:
+		state.Core.SetMemoryMaps(data)
+		achievements.UpdateMemory()
+	case libretro.EnvironmentSetInputDescriptors:
+		state.Core.SetInputDescriptors(data)
+	case libretro.EnvironmentSetControllerInfo:
+		state.Core.SetControllerInfo(data)
+	case libretro.EnvironmentSetSubsystemInfo:
+		state.Core.SetSubsystemInfo(data)
+	case libretro.EnvironmentGetVFSInterface:
+		return state.Core.SetVFSInterface(data, vfs.FS{})
+	case libretro.EnvironmentSetSupportNoGame:
+		state.Core.SupportNoGame = libretro.GetBool(data)
 	default:
 		//log.Println("[Env]: Not implemented:", cmd)
 		return false
@@ -0,0 +1,126 @@
+// Package headless runs a libretro core without window, audio device or
+// inputs. It is meant for automated testing of cores: a game is run for a fixed
+// number of frames, and each frame is hashed and optionally dumped to a PNG
+// file so regressions can be detected.
+package headless
+
+import (
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/libretro/ludo/core"
+	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/video"
+)
+
+// Options configures a headless run
+type Options struct {
+	Frames int    // number of frames to run
+	Dump   string // directory where to dump frames as PNG, disabled if empty
+}
+
+// Result holds the outcome of a headless run
+type Result struct {
+	Width, Height int      // dimensions of the last frame
+	Hashes        []uint32 // CRC32 of the RGBA pixels of each frame
+	AudioFrames   int      // number of stereo audio frames produced by the core
+	AudioHash     uint32   // CRC32 of all the audio samples
+}
+
+// sink receives the audio samples instead of an audio device
+type sink struct {
+	frames int
+	hash   uint32
+}
+
+func (s *sink) sample(left int16, right int16) {
+	buf := []byte{byte(left), byte(left >> 8), byte(right), byte(right >> 8)}
+	s.hash = crc32.Update(s.hash, crc32.IEEETable, buf)
+	s.frames++
+}
+
+func (s *sink) sampleBatch(buf []byte, size int32) int32 {
+	s.hash = crc32.Update(s.hash, crc32.IEEETable, buf)
+	s.frames += int(size)
+	return size
+}
+
+func inputPoll() {}
+
+func inputState(port uint, device uint32, index uint, id uint) int16 {
+	return 0
+}
+
+// Run loads a core and a game and runs it for o.Frames frames
+func Run(corePath, gamePath string, o Options) (*Result, error) {
+	state.Headless = true
+
+	vid := video.InitHeadless()
+	core.Init(vid)
+
+	if err := core.Load(corePath); err != nil {
+		return nil, err
+	}
+	defer core.Unload()
+
+	// Replace the callbacks that would need a window or an audio device
+	s := &sink{}
+	state.Core.SetInputPoll(inputPoll)
+	state.Core.SetInputState(inputState)
+	state.Core.SetAudioSample(s.sample)
+	state.Core.SetAudioSampleBatch(s.sampleBatch)
+
+	if err := core.LoadGame(gamePath); err != nil {
+		return nil, err
+	}
+
+	if o.Dump != "" {
+		if err := os.MkdirAll(o.Dump, os.ModePerm); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &Result{}
+	for i := 0; i < o.Frames; i++ {
+		state.Core.Run()
+		if state.Core.FrameTimeCallback != nil {
+			state.Core.FrameTimeCallback.Callback(state.Core.FrameTimeCallback.Reference)
+		}
+		if state.Core.AudioCallback != nil {
+			state.Core.AudioCallback.Callback()
+		}
+
+		frame := vid.Frame()
+		if frame == nil {
+			res.Hashes = append(res.Hashes, 0)
+			continue
+		}
+		res.Width = frame.Rect.Dx()
+		res.Height = frame.Rect.Dy()
+		res.Hashes = append(res.Hashes, crc32.ChecksumIEEE(frame.Pix))
+
+		if o.Dump != "" {
+			path := filepath.Join(o.Dump, fmt.Sprintf("%06d.png", i))
+			if err := dump(path, frame); err != nil {
+				return nil, err
+			}
+		}
+	}
+	res.AudioFrames = s.frames
+	res.AudioHash = s.hash
+
+	return res, nil
+}
+
+func dump(path string, frame *image.RGBA) error {
+	fd, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	return png.Encode(fd, frame)
+}
@@ -0,0 +1,39 @@
+package headless
+
+import (
+	"testing"
+
+	"github.com/libretro/ludo/utils"
+)
+
+func TestRun(t *testing.T) {
+	ext := utils.CoreExt()
+
+	res, err := Run("../core/testdata/vecx_libretro"+ext, "../core/testdata/Polar Rescue (USA).vec", Options{
+		Frames: 120,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	t.Run("Runs the requested number of frames", func(t *testing.T) {
+		if len(res.Hashes) != 120 {
+			t.Errorf("got = %v, want %v", len(res.Hashes), 120)
+		}
+	})
+
+	t.Run("Captures the frames rendered by the core", func(t *testing.T) {
+		if res.Width == 0 || res.Height == 0 {
+			t.Errorf("got = %vx%v, want a non empty frame", res.Width, res.Height)
+		}
+		if res.Hashes[len(res.Hashes)-1] == 0 {
+			t.Errorf("got = %v, want a frame hash", 0)
+		}
+	})
+
+	t.Run("Captures the audio produced by the core", func(t *testing.T) {
+		if res.AudioFrames == 0 {
+			t.Errorf("got = %v, want some audio frames", res.AudioFrames)
+		}
+	})
+}
@@ -19,23 +19,39 @@ type Game struct {
 	Savestate string // Absolute path of the last savestate on this game
 }
 
+// Key identifies a game in the history. Cores started without a game are
+// identified by the path of the core.
+func (g Game) Key() string {
+	if g.Path == "" {
+		return g.CorePath
+	}
+	return g.Path
+}
+
 // History is a list of games
 type History []Game
 
 // List is the list of recently played games
 var List History
 
-// Push pushes a game onto the stack
+// Push pushes a game onto the stack. The savestate of a game already in the
+// history is kept if g has none.
 func Push(g Game) {
+	for _, old := range List {
+		if old.Key() == g.Key() && g.Savestate == "" {
+			g.Savestate = old.Savestate
+			break
+		}
+	}
 	List = append([]Game{g}, List...)
 
 	// Deduplicate
 	l := History{}
 	exist := map[string]bool{}
 	for _, g := range List {
-		if !exist[g.Path] {
+		if !exist[g.Key()] {
 			l = append(l, g)
-			exist[g.Path] = true
+			exist[g.Key()] = true
 		}
 	}
 	List = l
@@ -60,6 +76,8 @@ func Load() error {
 	defer file.Close()
 
 	wr := csv.NewReader(bufio.NewReader(file))
+	// Older history files don't have the savestate column
+	wr.FieldsPerRecord = -1
 
 	List = History{}
 	for {
@@ -70,12 +88,19 @@ func Load() error {
 		if err != nil {
 			return err
 		}
-		List = append(List, Game{
+		if len(record) < 4 {
+			continue
+		}
+		game := Game{
 			Path:     record[0],
 			Name:     record[1],
 			System:   record[2],
 			CorePath: record[3],
-		})
+		}
+		if len(record) > 4 {
+			game.Savestate = record[4]
+		}
+		List = append(List, game)
 	}
 
 	return nil
@@ -103,8 +128,22 @@ func Save() error {
 			game.Name,
 			game.System,
 			game.CorePath,
+			game.Savestate,
 		})
 	}
 
 	return nil
 }
+
+// SetSavestate records the last savestate of a game. The path of the core
+// identifies cores started without a game.
+func SetSavestate(gamePath, corePath, savestate string) error {
+	key := Game{Path: gamePath, CorePath: corePath}.Key()
+	for i := range List {
+		if List[i].Key() == key {
+			List[i].Savestate = savestate
+			return Save()
+		}
+	}
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"github.com/libretro/ludo/libretro"
 )
 
-var joyBinds = map[glfw.GamepadButton]uint32{
+// defaultJoyBinds are the gamepad bindings of all players when no remapping
+// file overrides them
+var defaultJoyBinds = map[glfw.GamepadButton]uint32{
 	glfw.ButtonDpadUp:    libretro.DeviceIDJoypadUp,
 	glfw.ButtonDpadDown:  libretro.DeviceIDJoypadDown,
 	glfw.ButtonDpadLeft:  libretro.DeviceIDJoypadLeft,
@@ -26,3 +28,9 @@ var joyBinds = map[glfw.GamepadButton]uint32{
 	glfw.ButtonBack:  libretro.DeviceIDJoypadSelect,
 	glfw.ButtonGuide: ActionMenuToggle,
 }
+
+// defaultAxisBinds are the gamepad triggers bindings of all players
+var defaultAxisBinds = map[glfw.GamepadAxis]uint32{
+	glfw.AxisLeftTrigger:  libretro.DeviceIDJoypadL2,
+	glfw.AxisRightTrigger: libretro.DeviceIDJoypadR2,
+}
@@ -5,7 +5,9 @@ import (
 	"github.com/libretro/ludo/libretro"
 )
 
-var keyBinds = map[glfw.Key]uint32{
+// defaultKeyBinds are the keyboard bindings of the first player when no
+// remapping file overrides them
+var defaultKeyBinds = map[glfw.Key]uint32{
 	glfw.KeyX:          libretro.DeviceIDJoypadA,
 	glfw.KeyZ:          libretro.DeviceIDJoypadB,
 	glfw.KeyA:          libretro.DeviceIDJoypadY,
@@ -19,7 +21,14 @@ var keyBinds = map[glfw.Key]uint32{
 	glfw.KeyEnter:      libretro.DeviceIDJoypadStart,
 	glfw.KeyRightShift: libretro.DeviceIDJoypadSelect,
 	glfw.KeySpace:      ActionFastForwardToggle,
+	glfw.KeyR:          ActionRewind,
 	glfw.KeyP:          ActionMenuToggle,
 	glfw.KeyF:          ActionFullscreenToggle,
 	glfw.KeyEscape:     ActionShouldClose,
+	glfw.KeyF2:         ActionSaveState,
+	glfw.KeyF4:         ActionLoadState,
+	glfw.KeyF6:         ActionStateSlotPrev,
+	glfw.KeyF7:         ActionStateSlotNext,
+	glfw.KeyF9:         ActionRecordToggle,
+	glfw.KeyScrollLock: ActionGameFocusToggle,
 }
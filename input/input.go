@@ -24,12 +24,16 @@ type AnalogStates [MaxPlayers][2][2]int16
 var (
 	NewState States // input state for the current frame
 	OldState States // input state for the previous frame
-	Released States // keys just released during this frame
-	Pressed  States // keys just pressed during this frame
+	Released States // keys just released since the last ResetEdges
+	Pressed  States // keys just pressed since the last ResetEdges
 
 	NewAnalogState AnalogStates // analog input state for the current frame
 )
 
+// Replay, if set, is called by Poll after reading the devices. It can replace
+// the game inputs, for example to play back an input movie.
+var Replay func(*States, *AnalogStates)
+
 // Hot keys
 const (
 	// ActionMenuToggle toggles the menu UI
@@ -40,8 +44,23 @@ const (
 	ActionShouldClose uint32 = lr.DeviceIDJoypadR3 + 3
 	// ActionFastForwardToggle will run the core as fast as possible
 	ActionFastForwardToggle uint32 = lr.DeviceIDJoypadR3 + 4
+	// ActionRewind steps the game backwards while held
+	ActionRewind uint32 = lr.DeviceIDJoypadR3 + 5
+	// ActionSaveState saves the state in the current quick save slot
+	ActionSaveState uint32 = lr.DeviceIDJoypadR3 + 6
+	// ActionLoadState loads the state of the current quick save slot
+	ActionLoadState uint32 = lr.DeviceIDJoypadR3 + 7
+	// ActionStateSlotPrev selects the previous quick save slot
+	ActionStateSlotPrev uint32 = lr.DeviceIDJoypadR3 + 8
+	// ActionStateSlotNext selects the next quick save slot
+	ActionStateSlotNext uint32 = lr.DeviceIDJoypadR3 + 9
+	// ActionGameFocusToggle gives the keyboard to the game, disabling the
+	// keyboard hot keys
+	ActionGameFocusToggle uint32 = lr.DeviceIDJoypadR3 + 10
+	// ActionRecordToggle starts or stops recording a gameplay clip
+	ActionRecordToggle uint32 = lr.DeviceIDJoypadR3 + 11
 	// ActionLast is used for iterating
-	ActionLast uint32 = lr.DeviceIDJoypadR3 + 5
+	ActionLast uint32 = lr.DeviceIDJoypadR3 + 12
 )
 
 // joystickCallback is triggered when a joypad is plugged.
@@ -58,6 +77,8 @@ func joystickCallback(joy glfw.Joystick, event glfw.PeripheralEvent) {
 	default:
 		ntf.DisplayAndLog(ntf.Warning, "Input", "Joystick #%d unhandled event: %d.", joy, event)
 	}
+	// The ports of the force feedback devices may have changed
+	SetRumbler(newRumbler())
 }
 
 var vid *video.Video
@@ -66,6 +87,9 @@ var vid *video.Video
 func Init(v *video.Video) {
 	vid = v
 	glfw.SetJoystickCallback(joystickCallback)
+	if rumbler == nil {
+		SetRumbler(newRumbler())
+	}
 }
 
 func floatToAnalog(v float32) int16 {
@@ -84,19 +108,22 @@ func pollJoypads(state States, analogState AnalogStates) (States, AnalogStates)
 			continue
 		}
 
+		if p >= MaxPlayers {
+			break
+		}
+
 		// mapping pad buttons
-		for k, v := range joyBinds {
+		for k, v := range binds[p].buttons {
 			if pad.Buttons[k] == glfw.Press {
 				state[p][v] = 1
 			}
 		}
 
 		// mapping pad triggers
-		if pad.Axes[glfw.AxisLeftTrigger] > 0.5 {
-			state[p][lr.DeviceIDJoypadL2] = 1
-		}
-		if pad.Axes[glfw.AxisRightTrigger] > 0.5 {
-			state[p][lr.DeviceIDJoypadR2] = 1
+		for k, v := range binds[p].axes {
+			if pad.Axes[k] > 0.5 {
+				state[p][v] = 1
+			}
 		}
 
 		// mapping analog sticks
@@ -124,33 +151,59 @@ func pollJoypads(state States, analogState AnalogStates) (States, AnalogStates)
 	return state, analogState
 }
 
-// pollKeyboard processes keyboard keys
+// pollKeyboard processes keyboard keys. In game focus mode, the keyboard hot
+// keys are disabled, except the one leaving game focus.
 func pollKeyboard(state States) States {
-	for k, v := range keyBinds {
-		if vid.Window.GetKey(k) == glfw.Press {
-			state[0][v] = 1
+	for p := range binds {
+		for k, v := range binds[p].keys {
+			if gameFocus() && v > lr.DeviceIDJoypadR3 && v != ActionGameFocusToggle {
+				continue
+			}
+			if vid.Window.GetKey(k) == glfw.Press {
+				state[p][v] = 1
+			}
 		}
 	}
 	return state
 }
 
-// Compute the keys pressed or released during this frame
+// Compute the keys pressed or released between two polls
 func getPressedReleased(new States, old States) (States, States) {
+	var pressed, released States
 	for p := range new {
 		for k := range new[p] {
 			if new[p][k] == 1 && old[p][k] == 0 {
-				Pressed[p][k] = 1
-			} else {
-				Pressed[p][k] = 0
+				pressed[p][k] = 1
 			}
 			if new[p][k] == 0 && old[p][k] == 1 {
-				Released[p][k] = 1
-			} else {
-				Released[p][k] = 0
+				released[p][k] = 1
 			}
 		}
 	}
-	return Pressed, Released
+	return pressed, released
+}
+
+// updateEdges adds the keys pressed or released since the previous poll to
+// Pressed and Released. The core can poll several times between two runs of
+// the hot keys, or not at all, so the edges are kept until ResetEdges.
+func updateEdges() {
+	pressed, released := getPressedReleased(NewState, OldState)
+	for p := range pressed {
+		for k := range pressed[p] {
+			Pressed[p][k] |= pressed[p][k]
+			Released[p][k] |= released[p][k]
+		}
+	}
+
+	// Store the old input state for comparisions
+	OldState = NewState
+}
+
+// ResetEdges forgets the keys pressed and released. It is meant to be called
+// once per main loop iteration, after the hot keys are processed.
+func ResetEdges() {
+	Pressed = States{}
+	Released = States{}
 }
 
 // Poll calculates the input state. It is meant to be called for each frame.
@@ -158,10 +211,12 @@ func Poll() {
 	NewState = States{}
 	NewState, NewAnalogState = pollJoypads(NewState, NewAnalogState)
 	NewState = pollKeyboard(NewState)
-	Pressed, Released = getPressedReleased(NewState, OldState)
-
-	// Store the old input state for comparisions
-	OldState = NewState
+	NewMouseState = pollMouse(NewMouseState)
+	pollKeyboardEvents()
+	if Replay != nil {
+		Replay(&NewState, &NewAnalogState)
+	}
+	updateEdges()
 }
 
 // State is a callback passed to core.SetInputState
@@ -185,5 +240,21 @@ func State(port uint, device uint32, index uint, id uint) int16 {
 		return NewAnalogState[port][index][id]
 	}
 
+	if device == lr.DeviceKeyboard {
+		return keyboardInput(id)
+	}
+
+	if isMouseDevice(device) {
+		if device&lr.DeviceMask == lr.DeviceLightgun {
+			if b, ok := lightgunPad[uint32(id)]; ok {
+				return NewState[port][b]
+			}
+		}
+		if port != mousePort() {
+			return 0
+		}
+		return mouseInput(NewMouseState, device&lr.DeviceMask, index, id)
+	}
+
 	return 0
 }
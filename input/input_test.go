@@ -23,3 +23,60 @@ func Test_getPressedReleased(t *testing.T) {
 		}
 	})
 }
+
+func Test_updateEdges(t *testing.T) {
+	up := States{}
+	down := States{{0, 1}}
+
+	// Each main loop iteration runs the hot keys on the edges of the previous
+	// iterations, resets the edges, then runs a number of core frames that
+	// each poll once
+	tests := []struct {
+		name         string
+		frames       [][]States // the polls of each iteration
+		wantPressed  int        // the presses seen by the hot keys
+		wantReleased int        // the releases seen by the hot keys
+	}{
+		{
+			name:        "Sees a press once when no frame runs",
+			frames:      [][]States{{down}, {}, {}, {down}},
+			wantPressed: 1,
+		},
+		{
+			name:         "Sees a press and a release in the same iteration",
+			frames:       [][]States{{up, down, up, up}, {}},
+			wantPressed:  1,
+			wantReleased: 1,
+		},
+		{
+			name:         "Sees a release after several frames",
+			frames:       [][]States{{down}, {down, down, down, up}, {up, up}, {}},
+			wantPressed:  1,
+			wantReleased: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			NewState, OldState = States{}, States{}
+			ResetEdges()
+			pressed, released := 0, 0
+			for _, polls := range tt.frames {
+				pressed += int(Pressed[0][1])
+				released += int(Released[0][1])
+				ResetEdges()
+				for _, s := range polls {
+					NewState = s
+					updateEdges()
+				}
+			}
+			pressed += int(Pressed[0][1])
+			released += int(Released[0][1])
+			if pressed != tt.wantPressed {
+				t.Errorf("got = %v, want %v", pressed, tt.wantPressed)
+			}
+			if released != tt.wantReleased {
+				t.Errorf("got = %v, want %v", released, tt.wantReleased)
+			}
+		})
+	}
+}
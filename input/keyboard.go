@@ -0,0 +1,167 @@
+package input
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	lr "github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/state"
+)
+
+// retroKeys maps the GLFW keys to the libretro keys
+var retroKeys = map[glfw.Key]uint32{
+	glfw.KeySpace:        lr.KeySpace,
+	glfw.KeyApostrophe:   lr.KeyQuote,
+	glfw.KeyComma:        lr.KeyComma,
+	glfw.KeyMinus:        lr.KeyMinus,
+	glfw.KeyPeriod:       lr.KeyPeriod,
+	glfw.KeySlash:        lr.KeySlash,
+	glfw.KeySemicolon:    lr.KeySemicolon,
+	glfw.KeyEqual:        lr.KeyEquals,
+	glfw.KeyLeftBracket:  lr.KeyLeftBracket,
+	glfw.KeyBackslash:    lr.KeyBackslash,
+	glfw.KeyRightBracket: lr.KeyRightBracket,
+	glfw.KeyGraveAccent:  lr.KeyBackquote,
+	glfw.KeyWorld1:       lr.KeyOEM102,
+	glfw.KeyEscape:       lr.KeyEscape,
+	glfw.KeyEnter:        lr.KeyReturn,
+	glfw.KeyTab:          lr.KeyTab,
+	glfw.KeyBackspace:    lr.KeyBackspace,
+	glfw.KeyInsert:       lr.KeyInsert,
+	glfw.KeyDelete:       lr.KeyDelete,
+	glfw.KeyRight:        lr.KeyRight,
+	glfw.KeyLeft:         lr.KeyLeft,
+	glfw.KeyDown:         lr.KeyDown,
+	glfw.KeyUp:           lr.KeyUp,
+	glfw.KeyPageUp:       lr.KeyPageUp,
+	glfw.KeyPageDown:     lr.KeyPageDown,
+	glfw.KeyHome:         lr.KeyHome,
+	glfw.KeyEnd:          lr.KeyEnd,
+	glfw.KeyCapsLock:     lr.KeyCapsLock,
+	glfw.KeyScrollLock:   lr.KeyScrollLock,
+	glfw.KeyNumLock:      lr.KeyNumLock,
+	glfw.KeyPrintScreen:  lr.KeyPrint,
+	glfw.KeyPause:        lr.KeyPause,
+	glfw.KeyKPDecimal:    lr.KeyKPPeriod,
+	glfw.KeyKPDivide:     lr.KeyKPDivide,
+	glfw.KeyKPMultiply:   lr.KeyKPMultiply,
+	glfw.KeyKPSubtract:   lr.KeyKPMinus,
+	glfw.KeyKPAdd:        lr.KeyKPPlus,
+	glfw.KeyKPEnter:      lr.KeyKPEnter,
+	glfw.KeyKPEqual:      lr.KeyKPEquals,
+	glfw.KeyLeftShift:    lr.KeyLShift,
+	glfw.KeyLeftControl:  lr.KeyLCtrl,
+	glfw.KeyLeftAlt:      lr.KeyLAlt,
+	glfw.KeyLeftSuper:    lr.KeyLSuper,
+	glfw.KeyRightShift:   lr.KeyRShift,
+	glfw.KeyRightControl: lr.KeyRCtrl,
+	glfw.KeyRightAlt:     lr.KeyRAlt,
+	glfw.KeyRightSuper:   lr.KeyRSuper,
+	glfw.KeyMenu:         lr.KeyMenu,
+}
+
+func init() {
+	for k := glfw.KeyA; k <= glfw.KeyZ; k++ {
+		retroKeys[k] = lr.KeyA + uint32(k-glfw.KeyA)
+	}
+	for k := glfw.Key0; k <= glfw.Key9; k++ {
+		retroKeys[k] = lr.Key0 + uint32(k-glfw.Key0)
+	}
+	for k := glfw.KeyKP0; k <= glfw.KeyKP9; k++ {
+		retroKeys[k] = lr.KeyKP0 + uint32(k-glfw.KeyKP0)
+	}
+	for k := glfw.KeyF1; k <= glfw.KeyF15; k++ {
+		retroKeys[k] = lr.KeyF1 + uint32(k-glfw.KeyF1)
+	}
+}
+
+// retroModifiers converts GLFW modifiers to libretro modifiers
+func retroModifiers(mods glfw.ModifierKey) uint16 {
+	m := lr.KeyModNone
+	if mods&glfw.ModShift != 0 {
+		m |= lr.KeyModShift
+	}
+	if mods&glfw.ModControl != 0 {
+		m |= lr.KeyModCtrl
+	}
+	if mods&glfw.ModAlt != 0 {
+		m |= lr.KeyModAlt
+	}
+	if mods&glfw.ModSuper != 0 {
+		m |= lr.KeyModMeta
+	}
+	if mods&glfw.ModNumLock != 0 {
+		m |= lr.KeyModNumLock
+	}
+	if mods&glfw.ModCapsLock != 0 {
+		m |= lr.KeyModCapsLock
+	}
+	return m
+}
+
+// keyEvent is a keyboard event waiting to be sent to the core
+type keyEvent struct {
+	down      bool
+	keycode   uint32
+	character uint32
+	modifiers uint16
+}
+
+var (
+	retroKeyState  [lr.KeyLast]bool // libretro keys held
+	keyEvents      []keyEvent       // events received since the last poll
+	lastModifiers  uint16           // modifiers of the last key event
+	keyboardWindow *glfw.Window     // window the keyboard callbacks are set on
+)
+
+// keyCallback records the libretro key state and queues an event for the core
+func keyCallback(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	k, ok := retroKeys[key]
+	if !ok {
+		k = lr.KeyUnknown
+	}
+	lastModifiers = retroModifiers(mods)
+	down := action != glfw.Release
+	if k != lr.KeyUnknown {
+		retroKeyState[k] = down
+	}
+	keyEvents = append(keyEvents, keyEvent{down, k, 0, lastModifiers})
+}
+
+// charCallback queues a text event for the core. GLFW reports characters
+// separately from keys, they are sent as key down events without keycode.
+func charCallback(w *glfw.Window, char rune) {
+	keyEvents = append(keyEvents, keyEvent{true, lr.KeyUnknown, uint32(char), lastModifiers})
+}
+
+// pollKeyboardEvents sends the queued keyboard events to the core, if the
+// game is running
+func pollKeyboardEvents() {
+	// The window is recreated when toggling fullscreen
+	if vid.Window != keyboardWindow {
+		vid.Window.SetKeyCallback(keyCallback)
+		vid.Window.SetCharCallback(charCallback)
+		vid.Window.SetInputMode(glfw.LockKeyMods, glfw.True)
+		keyboardWindow = vid.Window
+	}
+
+	events := keyEvents
+	keyEvents = nil
+	if state.MenuActive || state.Core == nil || state.Core.KeyboardCallback == nil {
+		return
+	}
+	for _, e := range events {
+		state.Core.KeyboardCallback.Callback(e.down, e.keycode, e.character, e.modifiers)
+	}
+}
+
+// keyboardInput returns the state of a key of the keyboard device
+func keyboardInput(id uint) int16 {
+	if state.MenuActive || id >= uint(len(retroKeyState)) || !retroKeyState[id] {
+		return 0
+	}
+	return 1
+}
+
+// gameFocus tells if the keyboard is given to the game
+func gameFocus() bool {
+	return state.GameFocus && !state.MenuActive
+}
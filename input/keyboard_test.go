@@ -0,0 +1,36 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	lr "github.com/libretro/ludo/libretro"
+)
+
+func Test_retroKeys(t *testing.T) {
+	tests := []struct {
+		key  glfw.Key
+		want uint32
+	}{
+		{glfw.KeyA, lr.KeyA},
+		{glfw.KeyZ, lr.KeyZ},
+		{glfw.Key7, lr.Key7},
+		{glfw.KeyKP3, lr.KeyKP3},
+		{glfw.KeyF12, lr.KeyF12},
+		{glfw.KeyEnter, lr.KeyReturn},
+		{glfw.KeyRightControl, lr.KeyRCtrl},
+	}
+	for _, tt := range tests {
+		if got := retroKeys[tt.key]; got != tt.want {
+			t.Errorf("got = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func Test_retroModifiers(t *testing.T) {
+	got := retroModifiers(glfw.ModShift | glfw.ModSuper | glfw.ModCapsLock)
+	want := lr.KeyModShift | lr.KeyModMeta | lr.KeyModCapsLock
+	if got != want {
+		t.Errorf("got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,182 @@
+package input
+
+import (
+	"math"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	lr "github.com/libretro/ludo/libretro"
+)
+
+// mouseButtons maps the GLFW mouse buttons to the libretro mouse buttons
+var mouseButtons = map[glfw.MouseButton]uint32{
+	glfw.MouseButtonLeft:   lr.DeviceIDMouseLeft,
+	glfw.MouseButtonRight:  lr.DeviceIDMouseRight,
+	glfw.MouseButtonMiddle: lr.DeviceIDMouseMiddle,
+	glfw.MouseButton4:      lr.DeviceIDMouseButton4,
+	glfw.MouseButton5:      lr.DeviceIDMouseButton5,
+}
+
+// lightgunButtons maps the libretro lightgun buttons to the mouse buttons
+var lightgunButtons = map[uint32]uint32{
+	lr.DeviceIDLightgunTrigger: lr.DeviceIDMouseLeft,
+	lr.DeviceIDLightgunReload:  lr.DeviceIDMouseRight,
+	lr.DeviceIDLightgunAuxA:    lr.DeviceIDMouseMiddle,
+	lr.DeviceIDLightgunAuxB:    lr.DeviceIDMouseButton4,
+	lr.DeviceIDLightgunAuxC:    lr.DeviceIDMouseButton5,
+}
+
+// lightgunPad maps the libretro lightgun buttons to the RetroPad of the port
+var lightgunPad = map[uint32]uint32{
+	lr.DeviceIDLightgunStart:     lr.DeviceIDJoypadStart,
+	lr.DeviceIDLightgunSelect:    lr.DeviceIDJoypadSelect,
+	lr.DeviceIDLightgunDpadUp:    lr.DeviceIDJoypadUp,
+	lr.DeviceIDLightgunDpadDown:  lr.DeviceIDJoypadDown,
+	lr.DeviceIDLightgunDpadLeft:  lr.DeviceIDJoypadLeft,
+	lr.DeviceIDLightgunDpadRight: lr.DeviceIDJoypadRight,
+}
+
+// MouseState is the state of the mouse for a frame
+type MouseState struct {
+	X, Y    float64 // position of the cursor in window coordinates
+	DX, DY  int16   // motion since the previous frame
+	U, V    float32 // position of the cursor in the game frame, between 0 and 1
+	Inside  bool    // the cursor is inside of the game viewport
+	Buttons [lr.DeviceIDMouseButton5 + 1]int16
+}
+
+// NewMouseState is the mouse state for the current frame
+var NewMouseState MouseState
+
+var (
+	scrollX, scrollY float64      // scrolling accumulated since the last poll
+	mouseWindow      *glfw.Window // window the scroll callback is set on
+)
+
+// scrollCallback accumulates the scrolling between two polls
+func scrollCallback(w *glfw.Window, xoff float64, yoff float64) {
+	scrollX += xoff
+	scrollY += yoff
+}
+
+// clampInt16 converts a cursor motion to an int16
+func clampInt16(v float64) int16 {
+	return int16(math.Max(math.MinInt16, math.Min(math.MaxInt16, v)))
+}
+
+// pollMouse reads the cursor position, the mouse buttons and the scrolling
+func pollMouse(old MouseState) MouseState {
+	// The window is recreated when toggling fullscreen
+	if vid.Window != mouseWindow {
+		vid.Window.SetScrollCallback(scrollCallback)
+		mouseWindow = vid.Window
+		old.X, old.Y = vid.Window.GetCursorPos()
+	}
+
+	m := MouseState{}
+	m.X, m.Y = vid.Window.GetCursorPos()
+	m.DX = clampInt16(m.X - old.X)
+	m.DY = clampInt16(m.Y - old.Y)
+	m.U, m.V, m.Inside = vid.CursorToCore(m.X, m.Y)
+
+	for b, id := range mouseButtons {
+		if vid.Window.GetMouseButton(b) == glfw.Press {
+			m.Buttons[id] = 1
+		}
+	}
+
+	if scrollY > 0 {
+		m.Buttons[lr.DeviceIDMouseWheelUp] = 1
+	} else if scrollY < 0 {
+		m.Buttons[lr.DeviceIDMouseWheelDown] = 1
+	}
+	if scrollX > 0 {
+		m.Buttons[lr.DeviceIDMouseHorizWheelUp] = 1
+	} else if scrollX < 0 {
+		m.Buttons[lr.DeviceIDMouseHorizWheelDown] = 1
+	}
+	scrollX, scrollY = 0, 0
+
+	return m
+}
+
+// toAbsolute converts a coordinate in the game frame to the [-0x7fff, 0x7fff]
+// range used by pointers and lightguns
+func toAbsolute(c float32) int16 {
+	return int16((c*2 - 1) * 0x7fff)
+}
+
+// mouseInput returns the state of the mouse for the given device and id
+func mouseInput(m MouseState, device uint32, index uint, id uint) int16 {
+	switch device {
+	case lr.DeviceMouse:
+		switch uint32(id) {
+		case lr.DeviceIDMouseX:
+			return m.DX
+		case lr.DeviceIDMouseY:
+			return m.DY
+		}
+		if id < uint(len(m.Buttons)) {
+			return m.Buttons[id]
+		}
+	case lr.DevicePointer:
+		if index > 0 {
+			return 0
+		}
+		switch uint32(id) {
+		case lr.DeviceIDPointerX:
+			if !m.Inside {
+				return -0x8000
+			}
+			return toAbsolute(m.U)
+		case lr.DeviceIDPointerY:
+			if !m.Inside {
+				return -0x8000
+			}
+			return toAbsolute(m.V)
+		case lr.DeviceIDPointerPressed:
+			return m.Buttons[lr.DeviceIDMouseLeft]
+		case lr.DeviceIDPointerCount:
+			return 1
+		}
+	case lr.DeviceLightgun:
+		switch uint32(id) {
+		case lr.DeviceIDLightgunScreenX:
+			return toAbsolute(m.U)
+		case lr.DeviceIDLightgunScreenY:
+			return toAbsolute(m.V)
+		case lr.DeviceIDLightgunIsOffscreen:
+			if m.Inside {
+				return 0
+			}
+			return 1
+		case lr.DeviceIDLightgunX:
+			return m.DX
+		case lr.DeviceIDLightgunY:
+			return m.DY
+		}
+		if b, ok := lightgunButtons[uint32(id)]; ok {
+			return m.Buttons[b]
+		}
+	}
+	return 0
+}
+
+// isMouseDevice tells if a device reads the mouse
+func isMouseDevice(device uint32) bool {
+	switch device & lr.DeviceMask {
+	case lr.DeviceMouse, lr.DevicePointer, lr.DeviceLightgun:
+		return true
+	}
+	return false
+}
+
+// mousePort returns the port the mouse is plugged in. It is the first port set
+// to a mouse, pointer or lightgun device, or the first port.
+func mousePort() uint {
+	for p := range portDevices {
+		if isMouseDevice(portDevices[p]) {
+			return uint(p)
+		}
+	}
+	return 0
+}
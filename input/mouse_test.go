@@ -0,0 +1,40 @@
+package input
+
+import (
+	"testing"
+
+	lr "github.com/libretro/ludo/libretro"
+)
+
+func Test_mouseInput(t *testing.T) {
+	m := MouseState{DX: 3, DY: -2, U: 0.5, V: 1, Inside: true}
+	m.Buttons[lr.DeviceIDMouseLeft] = 1
+	off := m
+	off.Inside = false
+
+	tests := []struct {
+		name   string
+		m      MouseState
+		device uint32
+		index  uint
+		id     uint32
+		want   int16
+	}{
+		{"Mouse motion", m, lr.DeviceMouse, 0, lr.DeviceIDMouseY, -2},
+		{"Mouse button", m, lr.DeviceMouse, 0, lr.DeviceIDMouseLeft, 1},
+		{"Pointer center", m, lr.DevicePointer, 0, lr.DeviceIDPointerX, 0},
+		{"Pointer edge", m, lr.DevicePointer, 0, lr.DeviceIDPointerY, 0x7fff},
+		{"Pointer outside", off, lr.DevicePointer, 0, lr.DeviceIDPointerX, -0x8000},
+		{"Pointer second touch", m, lr.DevicePointer, 1, lr.DeviceIDPointerPressed, 0},
+		{"Lightgun trigger", m, lr.DeviceLightgun, 0, lr.DeviceIDLightgunTrigger, 1},
+		{"Lightgun offscreen", off, lr.DeviceLightgun, 0, lr.DeviceIDLightgunIsOffscreen, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mouseInput(tt.m, tt.device, tt.index, uint(tt.id))
+			if got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
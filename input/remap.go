@@ -0,0 +1,594 @@
+package input
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/settings"
+	"github.com/pelletier/go-toml"
+)
+
+// Devices that can be remapped
+const (
+	RemapKeyboard = "keyboard"
+	RemapJoypad   = "joypad"
+)
+
+// Binds maps the names of actions to the names of keys or gamepad buttons, as
+// stored in a remapping file
+type Binds struct {
+	Device   string            `toml:"device,omitempty"` // device plugged in the port of the player
+	Keyboard map[string]string `toml:"keyboard"`
+	Joypad   map[string]string `toml:"joypad"`
+}
+
+// Remap is the content of a remapping file. Binds are stored per player, with
+// keys like "player1".
+type Remap map[string]Binds
+
+// playerBinds are the resolved bindings of a player
+type playerBinds struct {
+	keys    map[glfw.Key]uint32
+	buttons map[glfw.GamepadButton]uint32
+	axes    map[glfw.GamepadAxis]uint32
+}
+
+// binds holds the bindings in use for all the players
+var binds [MaxPlayers]playerBinds
+
+// portDevices holds the libretro device plugged in each port
+var portDevices [MaxPlayers]uint32
+
+// Devices lists the devices that can be plugged in a port
+var Devices = []uint32{
+	libretro.DeviceJoypad,
+	libretro.DeviceAnalog,
+	libretro.DeviceMouse,
+	libretro.DevicePointer,
+	libretro.DeviceLightgun,
+	libretro.DeviceNone,
+}
+
+var deviceNames = map[uint32]string{
+	libretro.DeviceNone:     "none",
+	libretro.DeviceJoypad:   "joypad",
+	libretro.DeviceMouse:    "mouse",
+	libretro.DeviceLightgun: "lightgun",
+	libretro.DeviceAnalog:   "analog",
+	libretro.DevicePointer:  "pointer",
+}
+
+// deviceName returns the name of a device as used in remapping files. Devices
+// subclassed by cores are stored by number.
+func deviceName(device uint32) string {
+	if name, ok := deviceNames[device]; ok {
+		return name
+	}
+	return strconv.FormatUint(uint64(device), 10)
+}
+
+// deviceByName returns the device of a name found in a remapping file
+func deviceByName(name string) (uint32, error) {
+	for d, n := range deviceNames {
+		if n == name {
+			return d, nil
+		}
+	}
+	d, err := strconv.ParseUint(name, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("unknown device: %s", name)
+	}
+	return uint32(d), nil
+}
+
+// PortDevice returns the device plugged in a port
+func PortDevice(port uint) uint32 {
+	if port >= MaxPlayers {
+		return libretro.DeviceNone
+	}
+	return portDevices[port]
+}
+
+// The core and game the current bindings were loaded for
+var bindsCore, bindsGame string
+
+// Actions lists the actions that can be remapped, in menu order
+var Actions = []uint32{
+	libretro.DeviceIDJoypadUp,
+	libretro.DeviceIDJoypadDown,
+	libretro.DeviceIDJoypadLeft,
+	libretro.DeviceIDJoypadRight,
+	libretro.DeviceIDJoypadA,
+	libretro.DeviceIDJoypadB,
+	libretro.DeviceIDJoypadX,
+	libretro.DeviceIDJoypadY,
+	libretro.DeviceIDJoypadL,
+	libretro.DeviceIDJoypadR,
+	libretro.DeviceIDJoypadL2,
+	libretro.DeviceIDJoypadR2,
+	libretro.DeviceIDJoypadL3,
+	libretro.DeviceIDJoypadR3,
+	libretro.DeviceIDJoypadSelect,
+	libretro.DeviceIDJoypadStart,
+	ActionMenuToggle,
+	ActionFullscreenToggle,
+	ActionShouldClose,
+	ActionFastForwardToggle,
+	ActionRewind,
+	ActionSaveState,
+	ActionLoadState,
+	ActionStateSlotPrev,
+	ActionStateSlotNext,
+	ActionGameFocusToggle,
+	ActionRecordToggle,
+}
+
+var actionNames = map[uint32]string{
+	libretro.DeviceIDJoypadB:      "b",
+	libretro.DeviceIDJoypadY:      "y",
+	libretro.DeviceIDJoypadSelect: "select",
+	libretro.DeviceIDJoypadStart:  "start",
+	libretro.DeviceIDJoypadUp:     "up",
+	libretro.DeviceIDJoypadDown:   "down",
+	libretro.DeviceIDJoypadLeft:   "left",
+	libretro.DeviceIDJoypadRight:  "right",
+	libretro.DeviceIDJoypadA:      "a",
+	libretro.DeviceIDJoypadX:      "x",
+	libretro.DeviceIDJoypadL:      "l",
+	libretro.DeviceIDJoypadR:      "r",
+	libretro.DeviceIDJoypadL2:     "l2",
+	libretro.DeviceIDJoypadR2:     "r2",
+	libretro.DeviceIDJoypadL3:     "l3",
+	libretro.DeviceIDJoypadR3:     "r3",
+	ActionMenuToggle:              "menu_toggle",
+	ActionFullscreenToggle:        "fullscreen_toggle",
+	ActionShouldClose:             "should_close",
+	ActionFastForwardToggle:       "fast_forward_toggle",
+	ActionRewind:                  "rewind",
+	ActionSaveState:               "save_state",
+	ActionLoadState:               "load_state",
+	ActionStateSlotPrev:           "state_slot_prev",
+	ActionStateSlotNext:           "state_slot_next",
+	ActionGameFocusToggle:         "game_focus_toggle",
+	ActionRecordToggle:            "record_toggle",
+}
+
+// ActionName returns the name of an action as used in remapping files
+func ActionName(action uint32) string {
+	return actionNames[action]
+}
+
+var keyNames = map[glfw.Key]string{
+	glfw.KeySpace:        "space",
+	glfw.KeyApostrophe:   "apostrophe",
+	glfw.KeyComma:        "comma",
+	glfw.KeyMinus:        "minus",
+	glfw.KeyPeriod:       "period",
+	glfw.KeySlash:        "slash",
+	glfw.KeySemicolon:    "semicolon",
+	glfw.KeyEqual:        "equal",
+	glfw.KeyLeftBracket:  "left_bracket",
+	glfw.KeyBackslash:    "backslash",
+	glfw.KeyRightBracket: "right_bracket",
+	glfw.KeyGraveAccent:  "grave_accent",
+	glfw.KeyEscape:       "escape",
+	glfw.KeyEnter:        "enter",
+	glfw.KeyTab:          "tab",
+	glfw.KeyBackspace:    "backspace",
+	glfw.KeyInsert:       "insert",
+	glfw.KeyDelete:       "delete",
+	glfw.KeyRight:        "right",
+	glfw.KeyLeft:         "left",
+	glfw.KeyDown:         "down",
+	glfw.KeyUp:           "up",
+	glfw.KeyPageUp:       "page_up",
+	glfw.KeyPageDown:     "page_down",
+	glfw.KeyHome:         "home",
+	glfw.KeyEnd:          "end",
+	glfw.KeyCapsLock:     "caps_lock",
+	glfw.KeyScrollLock:   "scroll_lock",
+	glfw.KeyNumLock:      "num_lock",
+	glfw.KeyPrintScreen:  "print_screen",
+	glfw.KeyPause:        "pause",
+	glfw.KeyKPDecimal:    "kp_decimal",
+	glfw.KeyKPDivide:     "kp_divide",
+	glfw.KeyKPMultiply:   "kp_multiply",
+	glfw.KeyKPSubtract:   "kp_subtract",
+	glfw.KeyKPAdd:        "kp_add",
+	glfw.KeyKPEnter:      "kp_enter",
+	glfw.KeyKPEqual:      "kp_equal",
+	glfw.KeyLeftShift:    "left_shift",
+	glfw.KeyLeftControl:  "left_control",
+	glfw.KeyLeftAlt:      "left_alt",
+	glfw.KeyLeftSuper:    "left_super",
+	glfw.KeyRightShift:   "right_shift",
+	glfw.KeyRightControl: "right_control",
+	glfw.KeyRightAlt:     "right_alt",
+	glfw.KeyRightSuper:   "right_super",
+	glfw.KeyMenu:         "menu",
+}
+
+func init() {
+	for k := glfw.KeyA; k <= glfw.KeyZ; k++ {
+		keyNames[k] = string(rune('a' + k - glfw.KeyA))
+	}
+	for k := glfw.Key0; k <= glfw.Key9; k++ {
+		keyNames[k] = string(rune('0' + k - glfw.Key0))
+	}
+	for k := glfw.KeyKP0; k <= glfw.KeyKP9; k++ {
+		keyNames[k] = fmt.Sprintf("kp_%d", k-glfw.KeyKP0)
+	}
+	for k := glfw.KeyF1; k <= glfw.KeyF25; k++ {
+		keyNames[k] = fmt.Sprintf("f%d", k-glfw.KeyF1+1)
+	}
+	resetBinds()
+}
+
+var buttonNames = map[glfw.GamepadButton]string{
+	glfw.ButtonA:           "a",
+	glfw.ButtonB:           "b",
+	glfw.ButtonX:           "x",
+	glfw.ButtonY:           "y",
+	glfw.ButtonLeftBumper:  "left_bumper",
+	glfw.ButtonRightBumper: "right_bumper",
+	glfw.ButtonBack:        "back",
+	glfw.ButtonStart:       "start",
+	glfw.ButtonGuide:       "guide",
+	glfw.ButtonLeftThumb:   "left_thumb",
+	glfw.ButtonRightThumb:  "right_thumb",
+	glfw.ButtonDpadUp:      "dpad_up",
+	glfw.ButtonDpadRight:   "dpad_right",
+	glfw.ButtonDpadDown:    "dpad_down",
+	glfw.ButtonDpadLeft:    "dpad_left",
+}
+
+var axisNames = map[glfw.GamepadAxis]string{
+	glfw.AxisLeftTrigger:  "left_trigger",
+	glfw.AxisRightTrigger: "right_trigger",
+}
+
+// resetBinds restores the default bindings. Only the first player has
+// keyboard bindings.
+func resetBinds() {
+	for p := range binds {
+		portDevices[p] = libretro.DeviceJoypad
+		binds[p] = playerBinds{
+			keys:    map[glfw.Key]uint32{},
+			buttons: map[glfw.GamepadButton]uint32{},
+			axes:    map[glfw.GamepadAxis]uint32{},
+		}
+		if p == 0 {
+			for k, v := range defaultKeyBinds {
+				binds[p].keys[k] = v
+			}
+		}
+		for k, v := range defaultJoyBinds {
+			binds[p].buttons[k] = v
+		}
+		for k, v := range defaultAxisBinds {
+			binds[p].axes[k] = v
+		}
+	}
+}
+
+// actionByName returns the action of a name found in a remapping file
+func actionByName(name string) (uint32, bool) {
+	for k, v := range actionNames {
+		if v == name {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+// unbind removes the bindings of an action on a device
+func (pb *playerBinds) unbind(device string, action uint32) {
+	switch device {
+	case RemapKeyboard:
+		for k, v := range pb.keys {
+			if v == action {
+				delete(pb.keys, k)
+			}
+		}
+	case RemapJoypad:
+		for k, v := range pb.buttons {
+			if v == action {
+				delete(pb.buttons, k)
+			}
+		}
+		for k, v := range pb.axes {
+			if v == action {
+				delete(pb.axes, k)
+			}
+		}
+	}
+}
+
+// bind binds a key or a gamepad button to an action, replacing the previous
+// bindings of the action. An empty name leaves the action unbound.
+func (pb *playerBinds) bind(device string, action uint32, name string) error {
+	pb.unbind(device, action)
+	if name == "" {
+		return nil
+	}
+	switch device {
+	case RemapKeyboard:
+		for k, n := range keyNames {
+			if n == name {
+				pb.keys[k] = action
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown key: %s", name)
+	case RemapJoypad:
+		for b, n := range buttonNames {
+			if n == name {
+				pb.buttons[b] = action
+				return nil
+			}
+		}
+		for a, n := range axisNames {
+			if n == name {
+				pb.axes[a] = action
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown button: %s", name)
+	}
+	return fmt.Errorf("unknown device: %s", device)
+}
+
+// apply overrides the bindings of a player with the binds of a remapping file
+func (pb *playerBinds) apply(b Binds) error {
+	for device, m := range map[string]map[string]string{RemapKeyboard: b.Keyboard, RemapJoypad: b.Joypad} {
+		for a, name := range m {
+			action, ok := actionByName(a)
+			if !ok {
+				return fmt.Errorf("unknown action: %s", a)
+			}
+			if err := pb.bind(device, action, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// playerKey returns the key of a player in a remapping file
+func playerKey(player int) string {
+	return fmt.Sprintf("player%d", player+1)
+}
+
+// RemapPath returns the path of a remapping file. The global file is returned
+// if coreName is empty, the file of the core if gameName is empty.
+func RemapPath(coreName, gameName string) string {
+	dir := settings.Current.RemapsDirectory
+	if coreName == "" {
+		return filepath.Join(dir, "default.toml")
+	}
+	if gameName == "" {
+		return filepath.Join(dir, coreName, coreName+".toml")
+	}
+	return filepath.Join(dir, coreName, gameName+".toml")
+}
+
+// ReadRemap reads a remapping file. It returns nil if the file doesn't exist.
+func ReadRemap(path string) (Remap, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r := Remap{}
+	if err := toml.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// writeRemap writes a remapping file
+func writeRemap(path string, r Remap) error {
+	b, err := toml.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// LoadBinds loads the bindings of all players. The global remapping file is
+// overridden by the file of the core, which is overridden by the file of the
+// game. Pass empty names to load the global bindings only.
+func LoadBinds(coreName, gamePath string) error {
+	gameName := ""
+	if gamePath != "" {
+		gameName = strings.TrimSuffix(filepath.Base(gamePath), filepath.Ext(gamePath))
+	}
+	bindsCore, bindsGame = coreName, gameName
+
+	resetBinds()
+
+	paths := []string{RemapPath("", "")}
+	if coreName != "" {
+		paths = append(paths, RemapPath(coreName, ""))
+		if gameName != "" {
+			paths = append(paths, RemapPath(coreName, gameName))
+		}
+	}
+	for _, path := range paths {
+		r, err := ReadRemap(path)
+		if err != nil {
+			return err
+		}
+		for p := range binds {
+			b, ok := r[playerKey(p)]
+			if !ok {
+				continue
+			}
+			if err := binds[p].apply(b); err != nil {
+				return fmt.Errorf("%s: %v", filepath.Base(path), err)
+			}
+			if b.Device != "" {
+				d, err := deviceByName(b.Device)
+				if err != nil {
+					return fmt.Errorf("%s: %v", filepath.Base(path), err)
+				}
+				portDevices[p] = d
+			}
+		}
+	}
+	return nil
+}
+
+// ReloadBinds reloads the bindings of the current core and game
+func ReloadBinds() error {
+	return LoadBinds(bindsCore, bindsGame)
+}
+
+// RemapScopes returns the paths of the global, core and game remapping files
+// for the current bindings. The core and game paths are empty if no game is
+// loaded.
+func RemapScopes() (global, core, game string) {
+	global = RemapPath("", "")
+	if bindsCore != "" {
+		core = RemapPath(bindsCore, "")
+		if bindsGame != "" {
+			game = RemapPath(bindsCore, bindsGame)
+		}
+	}
+	return
+}
+
+// updateRemap changes the binds of a player in a remapping file and reloads
+// the bindings
+func updateRemap(path string, player int, update func(b *Binds) error) error {
+	r, err := ReadRemap(path)
+	if err != nil {
+		return err
+	}
+	if r == nil {
+		r = Remap{}
+	}
+	b := r[playerKey(player)]
+	if err := update(&b); err != nil {
+		return err
+	}
+	r[playerKey(player)] = b
+	if err := writeRemap(path, r); err != nil {
+		return err
+	}
+	return ReloadBinds()
+}
+
+// SaveBind stores a binding in a remapping file and reloads the bindings
+func SaveBind(path string, player int, action uint32, device, name string) error {
+	return updateRemap(path, player, func(b *Binds) error {
+		switch device {
+		case RemapKeyboard:
+			if b.Keyboard == nil {
+				b.Keyboard = map[string]string{}
+			}
+			b.Keyboard[ActionName(action)] = name
+		case RemapJoypad:
+			if b.Joypad == nil {
+				b.Joypad = map[string]string{}
+			}
+			b.Joypad[ActionName(action)] = name
+		default:
+			return fmt.Errorf("unknown device: %s", device)
+		}
+		return nil
+	})
+}
+
+// SavePortDevice stores the device plugged in the port of a player in a
+// remapping file and reloads the bindings
+func SavePortDevice(path string, player int, device uint32) error {
+	return updateRemap(path, player, func(b *Binds) error {
+		b.Device = deviceName(device)
+		return nil
+	})
+}
+
+// BindNames returns the names of the key and the gamepad button bound to an
+// action for a player. Names are empty for unbound devices.
+func BindNames(player int, action uint32) (key, button string) {
+	pb := binds[player]
+	for k, v := range pb.keys {
+		if v == action && (key == "" || keyNames[k] < key) {
+			key = keyNames[k]
+		}
+	}
+	for b, v := range pb.buttons {
+		if v == action && (button == "" || buttonNames[b] < button) {
+			button = buttonNames[b]
+		}
+	}
+	for a, v := range pb.axes {
+		if v == action && button == "" {
+			button = axisNames[a]
+		}
+	}
+	return
+}
+
+// AnyPressed tells if a key or a button of the gamepad of a player is held
+func AnyPressed(player int) bool {
+	_, _, ok := Capture(player)
+	return ok
+}
+
+// Capture returns the first key or gamepad button of a player found held. The
+// keyboard can be captured for every player, the gamepad is the one polled
+// for the player.
+func Capture(player int) (device, name string, ok bool) {
+	for k, n := range keyNames {
+		if vid.Window.GetKey(k) == glfw.Press {
+			return RemapKeyboard, n, true
+		}
+	}
+	p := 0
+	for joy := glfw.Joystick(0); joy < glfw.JoystickLast; joy++ {
+		if !joy.IsGamepad() {
+			continue
+		}
+		pad := joy.GetGamepadState()
+		if pad == nil {
+			continue
+		}
+		if p != player {
+			p++
+			continue
+		}
+		for b, n := range buttonNames {
+			if pad.Buttons[b] == glfw.Press {
+				return RemapJoypad, n, true
+			}
+		}
+		for a, n := range axisNames {
+			if pad.Axes[a] > 0.5 {
+				return RemapJoypad, n, true
+			}
+		}
+		break
+	}
+	return "", "", false
+}
+
+// ResetRemap deletes a remapping file and reloads the bindings
+func ResetRemap(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return ReloadBinds()
+}
@@ -0,0 +1,146 @@
+package input
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/settings"
+)
+
+func Test_apply(t *testing.T) {
+	t.Run("Overrides the bindings of an action", func(t *testing.T) {
+		resetBinds()
+		err := binds[0].apply(Binds{
+			Keyboard: map[string]string{"a": "k", "start": ""},
+			Joypad:   map[string]string{"l2": "left_bumper", "a": "right_trigger"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := binds[0].keys[glfw.KeyK]; got != libretro.DeviceIDJoypadA {
+			t.Errorf("got = %v, want %v", got, libretro.DeviceIDJoypadA)
+		}
+		if _, ok := binds[0].keys[glfw.KeyX]; ok {
+			t.Errorf("got = %v, want %v", ok, false)
+		}
+		if _, ok := binds[0].keys[glfw.KeyEnter]; ok {
+			t.Errorf("got = %v, want %v", ok, false)
+		}
+		if got := binds[0].buttons[glfw.ButtonLeftBumper]; got != libretro.DeviceIDJoypadL2 {
+			t.Errorf("got = %v, want %v", got, libretro.DeviceIDJoypadL2)
+		}
+		if got := binds[0].axes[glfw.AxisRightTrigger]; got != libretro.DeviceIDJoypadA {
+			t.Errorf("got = %v, want %v", got, libretro.DeviceIDJoypadA)
+		}
+		if _, ok := binds[0].axes[glfw.AxisLeftTrigger]; ok {
+			t.Errorf("got = %v, want %v", ok, false)
+		}
+	})
+
+	t.Run("Rejects unknown names", func(t *testing.T) {
+		resetBinds()
+		tests := []struct {
+			binds Binds
+			want  string
+		}{
+			{Binds{Keyboard: map[string]string{"jump": "k"}}, "unknown action: jump"},
+			{Binds{Keyboard: map[string]string{"a": "nope"}}, "unknown key: nope"},
+			{Binds{Joypad: map[string]string{"a": "nope"}}, "unknown button: nope"},
+		}
+		for _, tt := range tests {
+			err := binds[0].apply(tt.binds)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("got = %v, want %v", err, tt.want)
+			}
+		}
+	})
+}
+
+func Test_LoadBinds(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ludo-remaps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	settings.Current.RemapsDirectory = dir
+
+	write := func(path string, r Remap) {
+		if err := writeRemap(path, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(RemapPath("", ""), Remap{"player1": {Keyboard: map[string]string{"a": "j", "b": "k"}}})
+	write(RemapPath("VecX", ""), Remap{"player1": {Keyboard: map[string]string{"b": "l"}}})
+	write(RemapPath("VecX", "Mine Storm"), Remap{"player2": {Keyboard: map[string]string{"start": "enter"}}})
+
+	t.Run("Roundtrips remapping files", func(t *testing.T) {
+		got, err := ReadRemap(RemapPath("VecX", ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{"b": "l"}
+		if !reflect.DeepEqual(got["player1"].Keyboard, want) {
+			t.Errorf("got = %v, want %v", got["player1"].Keyboard, want)
+		}
+	})
+
+	t.Run("Game overrides core which overrides global", func(t *testing.T) {
+		if err := LoadBinds("VecX", "/roms/Mine Storm.bin"); err != nil {
+			t.Fatal(err)
+		}
+		tests := []struct {
+			player int
+			action uint32
+			want   string
+		}{
+			{0, libretro.DeviceIDJoypadA, "j"},
+			{0, libretro.DeviceIDJoypadB, "l"},
+			{0, libretro.DeviceIDJoypadY, "a"},
+			{1, libretro.DeviceIDJoypadStart, "enter"},
+			{1, libretro.DeviceIDJoypadA, ""},
+		}
+		for _, tt := range tests {
+			if got, _ := BindNames(tt.player, tt.action); got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		}
+	})
+
+	t.Run("Saves the device of a port", func(t *testing.T) {
+		path := RemapPath("VecX", "")
+		if err := SavePortDevice(path, 1, libretro.DeviceLightgun); err != nil {
+			t.Fatal(err)
+		}
+		if got := PortDevice(1); got != libretro.DeviceLightgun {
+			t.Errorf("got = %v, want %v", got, libretro.DeviceLightgun)
+		}
+		if got := mousePort(); got != 1 {
+			t.Errorf("got = %v, want %v", got, 1)
+		}
+	})
+
+	t.Run("Saves and resets a binding", func(t *testing.T) {
+		path := RemapPath("VecX", "")
+		if err := SaveBind(path, 0, ActionRewind, RemapJoypad, "guide"); err != nil {
+			t.Fatal(err)
+		}
+		if _, got := BindNames(0, ActionRewind); got != "guide" {
+			t.Errorf("got = %v, want %v", got, "guide")
+		}
+		if err := ResetRemap(path); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := BindNames(0, libretro.DeviceIDJoypadB); got != "k" {
+			t.Errorf("got = %v, want %v", got, "k")
+		}
+		if got := PortDevice(1); got != libretro.DeviceJoypad {
+			t.Errorf("got = %v, want %v", got, libretro.DeviceJoypad)
+		}
+	})
+
+	resetBinds()
+}
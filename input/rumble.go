@@ -0,0 +1,85 @@
+package input
+
+import (
+	"log"
+
+	lr "github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/settings"
+)
+
+// Rumbler drives the force feedback motors of the joypads
+type Rumbler interface {
+	// Rumble sets the strength of the strong and weak motors of the joypad
+	// plugged in a port. Strengths range from 0 to 0xffff.
+	Rumble(port uint, strong, weak uint16) error
+	// Close stops the motors and releases the devices
+	Close() error
+}
+
+var (
+	rumbler Rumbler
+	motors  [MaxPlayers][2]uint16 // strengths requested by the core
+)
+
+// SetRumbler replaces the force feedback backend
+func SetRumbler(r Rumbler) {
+	if rumbler != nil {
+		rumbler.Close()
+	}
+	rumbler = r
+	motors = [MaxPlayers][2]uint16{}
+}
+
+// SetRumbleState sets the strength of a motor of the joypad plugged in a port.
+// It is passed to the core as the rumble interface.
+func SetRumbleState(port uint, effect uint32, strength uint16) bool {
+	if rumbler == nil || port >= MaxPlayers || effect > lr.RumbleWeak {
+		return false
+	}
+	if motors[port][effect] == strength {
+		return true
+	}
+	motors[port][effect] = strength
+	return rumble(port)
+}
+
+// rumble sends the strengths requested for a port, scaled by the rumble
+// strength setting
+func rumble(port uint) bool {
+	scale := func(s uint16) uint16 {
+		v := float32(s) * settings.Current.InputRumbleStrength
+		if v > 0xffff {
+			return 0xffff
+		}
+		if v < 0 {
+			return 0
+		}
+		return uint16(v)
+	}
+	strong, weak := motors[port][lr.RumbleStrong], motors[port][lr.RumbleWeak]
+	if err := rumbler.Rumble(port, scale(strong), scale(weak)); err != nil {
+		log.Println("[Input]: Rumble failed:", err)
+		return false
+	}
+	return true
+}
+
+// UpdateRumble applies a new rumble strength setting to the running motors
+func UpdateRumble() {
+	if rumbler == nil {
+		return
+	}
+	for port := range motors {
+		if motors[port] != [2]uint16{} {
+			rumble(uint(port))
+		}
+	}
+}
+
+// StopRumble stops the motors of all the joypads
+func StopRumble() {
+	for port := range motors {
+		SetRumbleState(uint(port), lr.RumbleStrong, 0)
+		SetRumbleState(uint(port), lr.RumbleWeak, 0)
+	}
+}
@@ -0,0 +1,153 @@
+package input
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"unsafe"
+)
+
+// Linux input event codes, see linux/input.h and linux/input-event-codes.h
+const (
+	evFF      = 0x15
+	ffRumble  = 0x50
+	ffMax     = 0x7f
+	iocWrite  = 1
+	iocRead   = 2
+	ptrSize   = int(unsafe.Sizeof(uintptr(0)))
+	unionSize = 24 + ptrSize // size of the union of struct ff_effect
+)
+
+// ffEffect mirrors struct ff_effect. The union starts with the magnitudes of
+// struct ff_rumble_effect.
+type ffEffect struct {
+	Type      uint16
+	ID        int16
+	Direction uint16
+	Trigger   [2]uint16
+	Replay    [2]uint16 // length and delay
+	_         [2]byte
+	U         [unionSize]byte
+}
+
+// inputEvent mirrors struct input_event
+type inputEvent struct {
+	Time  syscall.Timeval
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+func ioc(dir, nr, size int) uintptr {
+	return uintptr(dir<<30 | size<<16 | 'E'<<8 | nr)
+}
+
+func ioctl(fd uintptr, req uintptr, arg unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// evdevDevice is an event device supporting FF_RUMBLE
+type evdevDevice struct {
+	file *os.File
+	id   int16 // id of the uploaded effect, -1 if none
+}
+
+// evdevRumbler drives the motors through the Linux force feedback API. Rumble
+// capable event devices are assigned to the ports in the order of their
+// event number.
+type evdevRumbler struct {
+	devices []*evdevDevice
+}
+
+// newRumbler returns the force feedback backend of the platform
+func newRumbler() Rumbler {
+	r, err := newEvdevRumbler("/dev/input")
+	if err != nil {
+		log.Println("[Input]: Rumble not available:", err)
+		return nil
+	}
+	return r
+}
+
+// newEvdevRumbler opens the event devices of dir that support FF_RUMBLE
+func newEvdevRumbler(dir string) (*evdevRumbler, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "event*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		var a, b int
+		fmt.Sscanf(filepath.Base(paths[i]), "event%d", &a)
+		fmt.Sscanf(filepath.Base(paths[j]), "event%d", &b)
+		return a < b
+	})
+
+	r := &evdevRumbler{}
+	for _, path := range paths {
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			continue
+		}
+		var bits [ffMax/8 + 1]byte
+		err = ioctl(f.Fd(), ioc(iocRead, 0x20+evFF, len(bits)), unsafe.Pointer(&bits[0]))
+		if err != nil || bits[ffRumble/8]&(1<<(ffRumble%8)) == 0 {
+			f.Close()
+			continue
+		}
+		r.devices = append(r.devices, &evdevDevice{file: f, id: -1})
+	}
+	return r, nil
+}
+
+// Rumble uploads a rumble effect with the given strengths and plays it, or
+// stops it if both strengths are 0
+func (r *evdevRumbler) Rumble(port uint, strong, weak uint16) error {
+	if port >= uint(len(r.devices)) {
+		return nil
+	}
+	d := r.devices[port]
+
+	if strong == 0 && weak == 0 {
+		if d.id < 0 {
+			return nil
+		}
+		return d.play(0)
+	}
+
+	e := ffEffect{Type: ffRumble, ID: d.id}
+	*(*uint16)(unsafe.Pointer(&e.U[0])) = strong
+	*(*uint16)(unsafe.Pointer(&e.U[2])) = weak
+	// A length of 0 plays the effect until it is stopped
+	if err := ioctl(d.file.Fd(), ioc(iocWrite, 0x80, int(unsafe.Sizeof(e))), unsafe.Pointer(&e)); err != nil {
+		return err
+	}
+	d.id = e.ID
+	return d.play(1)
+}
+
+// play starts or stops the effect of a device
+func (d *evdevDevice) play(value int32) error {
+	ev := inputEvent{Type: evFF, Code: uint16(d.id), Value: value}
+	b := (*[unsafe.Sizeof(ev)]byte)(unsafe.Pointer(&ev))[:]
+	_, err := d.file.Write(b)
+	return err
+}
+
+// Close stops the effects and closes the devices
+func (r *evdevRumbler) Close() error {
+	for _, d := range r.devices {
+		if d.id >= 0 {
+			d.play(0)
+		}
+		d.file.Close()
+	}
+	r.devices = nil
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package input
+
+// newRumbler returns the force feedback backend of the platform. There is
+// none yet outside of Linux.
+func newRumbler() Rumbler {
+	return nil
+}
@@ -0,0 +1,89 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+
+	lr "github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/settings"
+)
+
+// fakeRumbler records the strengths of the motors of each port
+type fakeRumbler struct {
+	motors map[uint][2]uint16
+	closed bool
+}
+
+func (r *fakeRumbler) Rumble(port uint, strong, weak uint16) error {
+	r.motors[port] = [2]uint16{strong, weak}
+	return nil
+}
+
+func (r *fakeRumbler) Close() error {
+	r.closed = true
+	return nil
+}
+
+func Test_SetRumbleState(t *testing.T) {
+	r := &fakeRumbler{motors: map[uint][2]uint16{}}
+	SetRumbler(r)
+	defer SetRumbler(nil)
+	settings.Current.InputRumbleStrength = 0.5
+	defer func() { settings.Current.InputRumbleStrength = 1 }()
+
+	t.Run("Routes the motors per port", func(t *testing.T) {
+		SetRumbleState(1, lr.RumbleStrong, 0x8000)
+		SetRumbleState(1, lr.RumbleWeak, 0x2000)
+		SetRumbleState(3, lr.RumbleWeak, 0xffff)
+		want := map[uint][2]uint16{1: {0x4000, 0x1000}, 3: {0, 0x7fff}}
+		if !reflect.DeepEqual(r.motors, want) {
+			t.Errorf("got = %v, want %v", r.motors, want)
+		}
+	})
+
+	t.Run("Applies a new strength to the running motors", func(t *testing.T) {
+		settings.Current.InputRumbleStrength = 0.25
+		UpdateRumble()
+		want := map[uint][2]uint16{1: {0x2000, 0x800}, 3: {0, 0x3fff}}
+		if !reflect.DeepEqual(r.motors, want) {
+			t.Errorf("got = %v, want %v", r.motors, want)
+		}
+	})
+
+	t.Run("Clamps the scaled strengths", func(t *testing.T) {
+		settings.Current.InputRumbleStrength = 2
+		UpdateRumble()
+		want := map[uint][2]uint16{1: {0xffff, 0x4000}, 3: {0, 0xffff}}
+		if !reflect.DeepEqual(r.motors, want) {
+			t.Errorf("got = %v, want %v", r.motors, want)
+		}
+		settings.Current.InputRumbleStrength = 0.5
+	})
+
+	t.Run("Rejects invalid ports and effects", func(t *testing.T) {
+		if got := SetRumbleState(MaxPlayers, lr.RumbleStrong, 1); got {
+			t.Errorf("got = %v, want %v", got, false)
+		}
+		if got := SetRumbleState(0, 2, 1); got {
+			t.Errorf("got = %v, want %v", got, false)
+		}
+	})
+
+	t.Run("Stops all the motors", func(t *testing.T) {
+		StopRumble()
+		want := map[uint][2]uint16{1: {0, 0}, 3: {0, 0}}
+		if !reflect.DeepEqual(r.motors, want) {
+			t.Errorf("got = %v, want %v", r.motors, want)
+		}
+	})
+
+	t.Run("Closes the previous backend", func(t *testing.T) {
+		SetRumbler(nil)
+		if !r.closed {
+			t.Errorf("got = %v, want %v", r.closed, true)
+		}
+		if got := SetRumbleState(0, lr.RumbleStrong, 1); got {
+			t.Errorf("got = %v, want %v", got, false)
+		}
+	})
+}
@@ -30,6 +30,10 @@ void bridge_retro_audio_set_state(retro_audio_set_state_callback_t f, bool state
 	f(state);
 }
 
+void bridge_retro_keyboard_event(retro_keyboard_event_t f, bool down, unsigned keycode, uint32_t character, uint16_t key_modifiers) {
+	f(down, keycode, character, key_modifiers);
+}
+
 void bridge_retro_get_system_info(void *f, struct retro_system_info *si) {
   return ((void (*)(struct retro_system_info *))f)(si);
 }
@@ -70,6 +74,10 @@ bool bridge_retro_load_game(void *f, struct retro_game_info *gi) {
   return ((bool (*)(struct retro_game_info *))f)(gi);
 }
 
+bool bridge_retro_load_game_special(void *f, unsigned type, struct retro_game_info *gi, size_t num) {
+  return ((bool (*)(unsigned, struct retro_game_info *, size_t))f)(type, gi, num);
+}
+
 size_t bridge_retro_serialize_size(void *f) {
   return ((size_t (*)(void))f)();
 }
@@ -102,6 +110,14 @@ void* bridge_retro_get_memory_data(void *f, unsigned id) {
 	return ((void* (*)(unsigned))f)(id);
 }
 
+void bridge_retro_cheat_reset(void *f) {
+	return ((void (*)(void))f)();
+}
+
+void bridge_retro_cheat_set(void *f, unsigned index, bool enabled, const char *code) {
+	return ((void (*)(unsigned, bool, const char*))f)(index, enabled, code);
+}
+
 void bridge_retro_set_eject_state(retro_set_eject_state_t f, bool state) {
 	f(state);
 }
@@ -122,6 +138,10 @@ unsigned bridge_retro_get_num_images(retro_get_num_images_t f) {
 	return ((unsigned (*)())f)();
 }
 
+void bridge_retro_hw_context_reset(retro_hw_context_reset_t f) {
+	f();
+}
+
 bool coreEnvironment_cgo(unsigned cmd, void *data) {
 	bool coreEnvironment(unsigned, void*);
 	return coreEnvironment(cmd, data);
@@ -168,5 +188,115 @@ int64_t coreGetTimeUsec_cgo() {
 	return coreGetTimeUsec();
 }
 
+uintptr_t coreGetCurrentFramebuffer_cgo() {
+	uintptr_t coreGetCurrentFramebuffer();
+	return coreGetCurrentFramebuffer();
+}
+
+retro_proc_address_t coreGetProcAddress_cgo(const char *sym) {
+	void* coreGetProcAddress(const char*);
+	return (retro_proc_address_t)coreGetProcAddress(sym);
+}
+
+bool coreSetRumbleState_cgo(unsigned port, enum retro_rumble_effect effect, uint16_t strength) {
+	bool coreSetRumbleState(unsigned, enum retro_rumble_effect, uint16_t);
+	return coreSetRumbleState(port, effect, strength);
+}
+
+const char *coreVFSGetPath_cgo(struct retro_vfs_file_handle *stream) {
+	const char *coreVFSGetPath(void*);
+	return coreVFSGetPath(stream);
+}
+
+struct retro_vfs_file_handle *coreVFSOpen_cgo(const char *path, unsigned mode, unsigned hints) {
+	void *coreVFSOpen(const char*, unsigned, unsigned);
+	return coreVFSOpen(path, mode, hints);
+}
+
+int coreVFSClose_cgo(struct retro_vfs_file_handle *stream) {
+	int coreVFSClose(void*);
+	return coreVFSClose(stream);
+}
+
+int64_t coreVFSSize_cgo(struct retro_vfs_file_handle *stream) {
+	int64_t coreVFSSize(void*);
+	return coreVFSSize(stream);
+}
+
+int64_t coreVFSTruncate_cgo(struct retro_vfs_file_handle *stream, int64_t length) {
+	int64_t coreVFSTruncate(void*, int64_t);
+	return coreVFSTruncate(stream, length);
+}
+
+int64_t coreVFSTell_cgo(struct retro_vfs_file_handle *stream) {
+	int64_t coreVFSTell(void*);
+	return coreVFSTell(stream);
+}
+
+int64_t coreVFSSeek_cgo(struct retro_vfs_file_handle *stream, int64_t offset, int seek_position) {
+	int64_t coreVFSSeek(void*, int64_t, int);
+	return coreVFSSeek(stream, offset, seek_position);
+}
+
+int64_t coreVFSRead_cgo(struct retro_vfs_file_handle *stream, void *s, uint64_t len) {
+	int64_t coreVFSRead(void*, void*, uint64_t);
+	return coreVFSRead(stream, s, len);
+}
+
+int64_t coreVFSWrite_cgo(struct retro_vfs_file_handle *stream, const void *s, uint64_t len) {
+	int64_t coreVFSWrite(void*, void*, uint64_t);
+	return coreVFSWrite(stream, (void*)s, len);
+}
+
+int coreVFSFlush_cgo(struct retro_vfs_file_handle *stream) {
+	int coreVFSFlush(void*);
+	return coreVFSFlush(stream);
+}
+
+int coreVFSRemove_cgo(const char *path) {
+	int coreVFSRemove(const char*);
+	return coreVFSRemove(path);
+}
+
+int coreVFSRename_cgo(const char *old_path, const char *new_path) {
+	int coreVFSRename(const char*, const char*);
+	return coreVFSRename(old_path, new_path);
+}
+
+int coreVFSStat_cgo(const char *path, int32_t *size) {
+	int coreVFSStat(const char*, int32_t*);
+	return coreVFSStat(path, size);
+}
+
+int coreVFSMkdir_cgo(const char *dir) {
+	int coreVFSMkdir(const char*);
+	return coreVFSMkdir(dir);
+}
+
+struct retro_vfs_dir_handle *coreVFSOpendir_cgo(const char *dir, bool include_hidden) {
+	void *coreVFSOpendir(const char*, bool);
+	return coreVFSOpendir(dir, include_hidden);
+}
+
+bool coreVFSReaddir_cgo(struct retro_vfs_dir_handle *dirstream) {
+	bool coreVFSReaddir(void*);
+	return coreVFSReaddir(dirstream);
+}
+
+const char *coreVFSDirentGetName_cgo(struct retro_vfs_dir_handle *dirstream) {
+	const char *coreVFSDirentGetName(void*);
+	return coreVFSDirentGetName(dirstream);
+}
+
+bool coreVFSDirentIsDir_cgo(struct retro_vfs_dir_handle *dirstream) {
+	bool coreVFSDirentIsDir(void*);
+	return coreVFSDirentIsDir(dirstream);
+}
+
+int coreVFSClosedir_cgo(struct retro_vfs_dir_handle *dirstream) {
+	int coreVFSClosedir(void*);
+	return coreVFSClosedir(dirstream);
+}
+
 */
 import "C"
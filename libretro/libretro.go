@@ -26,6 +26,7 @@ void bridge_retro_set_input_state(void *f, void *callback);
 void bridge_retro_set_audio_sample(void *f, void *callback);
 void bridge_retro_set_audio_sample_batch(void *f, void *callback);
 bool bridge_retro_load_game(void *f, struct retro_game_info *gi);
+bool bridge_retro_load_game_special(void *f, unsigned type, struct retro_game_info *gi, size_t num);
 bool bridge_retro_serialize(void *f, void *data, size_t size);
 bool bridge_retro_unserialize(void *f, void *data, size_t size);
 size_t bridge_retro_serialize_size(void *f);
@@ -35,6 +36,7 @@ void bridge_retro_reset(void *f);
 void bridge_retro_frame_time_callback(retro_frame_time_callback_t f, retro_usec_t usec);
 void bridge_retro_audio_callback(retro_audio_callback_t f);
 void bridge_retro_audio_set_state(retro_audio_set_state_callback_t f, bool state);
+void bridge_retro_keyboard_event(retro_keyboard_event_t f, bool down, unsigned keycode, uint32_t character, uint16_t key_modifiers);
 size_t bridge_retro_get_memory_size(void *f, unsigned id);
 void* bridge_retro_get_memory_data(void *f, unsigned id);
 void bridge_retro_set_eject_state(retro_set_eject_state_t f, bool state);
@@ -42,6 +44,9 @@ bool bridge_retro_get_eject_state(retro_get_eject_state_t f);
 unsigned bridge_retro_get_image_index(retro_get_image_index_t f);
 void bridge_retro_set_image_index(retro_set_image_index_t f, unsigned index);
 unsigned bridge_retro_get_num_images(retro_get_num_images_t f);
+void bridge_retro_cheat_reset(void *f);
+void bridge_retro_cheat_set(void *f, unsigned index, bool enabled, const char *code);
+void bridge_retro_hw_context_reset(retro_hw_context_reset_t f);
 
 bool coreEnvironment_cgo(unsigned cmd, void *data);
 void coreVideoRefresh_cgo(void *data, unsigned width, unsigned height, size_t pitch);
@@ -51,11 +56,36 @@ size_t coreAudioSampleBatch_cgo(const int16_t *data, size_t frames);
 int16_t coreInputState_cgo(unsigned port, unsigned device, unsigned index, unsigned id);
 void coreLog_cgo(enum retro_log_level level, const char *msg);
 int64_t coreGetTimeUsec_cgo();
+uintptr_t coreGetCurrentFramebuffer_cgo();
+retro_proc_address_t coreGetProcAddress_cgo(const char *sym);
+bool coreSetRumbleState_cgo(unsigned port, enum retro_rumble_effect effect, uint16_t strength);
+const char *coreVFSGetPath_cgo(struct retro_vfs_file_handle *stream);
+struct retro_vfs_file_handle *coreVFSOpen_cgo(const char *path, unsigned mode, unsigned hints);
+int coreVFSClose_cgo(struct retro_vfs_file_handle *stream);
+int64_t coreVFSSize_cgo(struct retro_vfs_file_handle *stream);
+int64_t coreVFSTruncate_cgo(struct retro_vfs_file_handle *stream, int64_t length);
+int64_t coreVFSTell_cgo(struct retro_vfs_file_handle *stream);
+int64_t coreVFSSeek_cgo(struct retro_vfs_file_handle *stream, int64_t offset, int seek_position);
+int64_t coreVFSRead_cgo(struct retro_vfs_file_handle *stream, void *s, uint64_t len);
+int64_t coreVFSWrite_cgo(struct retro_vfs_file_handle *stream, const void *s, uint64_t len);
+int coreVFSFlush_cgo(struct retro_vfs_file_handle *stream);
+int coreVFSRemove_cgo(const char *path);
+int coreVFSRename_cgo(const char *old_path, const char *new_path);
+int coreVFSStat_cgo(const char *path, int32_t *size);
+int coreVFSMkdir_cgo(const char *dir);
+struct retro_vfs_dir_handle *coreVFSOpendir_cgo(const char *dir, bool include_hidden);
+bool coreVFSReaddir_cgo(struct retro_vfs_dir_handle *dirstream);
+const char *coreVFSDirentGetName_cgo(struct retro_vfs_dir_handle *dirstream);
+bool coreVFSDirentIsDir_cgo(struct retro_vfs_dir_handle *dirstream);
+int coreVFSClosedir_cgo(struct retro_vfs_dir_handle *dirstream);
 */
 import "C"
 import (
 	"errors"
+	"io"
+	"os"
 	"strings"
+	"sync"
 	"unsafe"
 )
 
@@ -210,6 +240,42 @@ type AudioCallback struct {
 	SetState func(bool)
 }
 
+// KeyboardCallback stores the callback used to notify the core about keyboard
+// events
+type KeyboardCallback struct {
+	Callback func(down bool, keycode uint32, character uint32, modifiers uint16)
+}
+
+// HWRenderCallback stores the hardware rendering context requested by the core
+type HWRenderCallback struct {
+	ContextType      uint32
+	Depth            bool
+	Stencil          bool
+	BottomLeftOrigin bool
+	VersionMajor     uint
+	VersionMinor     uint
+	CacheContext     bool
+	ContextReset     func()
+	ContextDestroy   func()
+}
+
+// Rumble effects
+const (
+	RumbleStrong = uint32(C.RETRO_RUMBLE_STRONG)
+	RumbleWeak   = uint32(C.RETRO_RUMBLE_WEAK)
+)
+
+// Hardware context types
+const (
+	HWContextNone            = uint32(C.RETRO_HW_CONTEXT_NONE)
+	HWContextOpenGL          = uint32(C.RETRO_HW_CONTEXT_OPENGL)
+	HWContextOpenGLES2       = uint32(C.RETRO_HW_CONTEXT_OPENGLES2)
+	HWContextOpenGLCore      = uint32(C.RETRO_HW_CONTEXT_OPENGL_CORE)
+	HWContextOpenGLES3       = uint32(C.RETRO_HW_CONTEXT_OPENGLES3)
+	HWContextOpenGLESVersion = uint32(C.RETRO_HW_CONTEXT_OPENGLES_VERSION)
+	HWContextVulkan          = uint32(C.RETRO_HW_CONTEXT_VULKAN)
+)
+
 // The pixel format the core must use to render into data.
 // This format could differ from the format used in SET_PIXEL_FORMAT.
 // Set by frontend in GET_CURRENT_SOFTWARE_FRAMEBUFFER.
@@ -265,6 +331,58 @@ const (
 	// Positive Y axis is down.
 	// Only use ANALOG type when polling for analog values of the axes.
 	DeviceAnalog = uint32(C.RETRO_DEVICE_ANALOG)
+
+	// DevicePointer is an abstraction around touch screens and mice reporting
+	// absolute coordinates, in the range [-0x7fff, 0x7fff] of the game
+	// viewport. -0x8000 is returned for coordinates outside the viewport.
+	DevicePointer = uint32(C.RETRO_DEVICE_POINTER)
+)
+
+// DeviceMask extracts the base device of a device subclassed by a core with
+// RETRO_DEVICE_SUBCLASS
+const DeviceMask = uint32(C.RETRO_DEVICE_MASK)
+
+// Id values for MOUSE.
+const (
+	DeviceIDMouseX              = uint32(C.RETRO_DEVICE_ID_MOUSE_X)
+	DeviceIDMouseY              = uint32(C.RETRO_DEVICE_ID_MOUSE_Y)
+	DeviceIDMouseLeft           = uint32(C.RETRO_DEVICE_ID_MOUSE_LEFT)
+	DeviceIDMouseRight          = uint32(C.RETRO_DEVICE_ID_MOUSE_RIGHT)
+	DeviceIDMouseWheelUp        = uint32(C.RETRO_DEVICE_ID_MOUSE_WHEELUP)
+	DeviceIDMouseWheelDown      = uint32(C.RETRO_DEVICE_ID_MOUSE_WHEELDOWN)
+	DeviceIDMouseMiddle         = uint32(C.RETRO_DEVICE_ID_MOUSE_MIDDLE)
+	DeviceIDMouseHorizWheelUp   = uint32(C.RETRO_DEVICE_ID_MOUSE_HORIZ_WHEELUP)
+	DeviceIDMouseHorizWheelDown = uint32(C.RETRO_DEVICE_ID_MOUSE_HORIZ_WHEELDOWN)
+	DeviceIDMouseButton4        = uint32(C.RETRO_DEVICE_ID_MOUSE_BUTTON_4)
+	DeviceIDMouseButton5        = uint32(C.RETRO_DEVICE_ID_MOUSE_BUTTON_5)
+)
+
+// Id values for LIGHTGUN.
+const (
+	DeviceIDLightgunScreenX     = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_SCREEN_X)
+	DeviceIDLightgunScreenY     = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_SCREEN_Y)
+	DeviceIDLightgunIsOffscreen = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_IS_OFFSCREEN)
+	DeviceIDLightgunTrigger     = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_TRIGGER)
+	DeviceIDLightgunReload      = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_RELOAD)
+	DeviceIDLightgunAuxA        = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_AUX_A)
+	DeviceIDLightgunAuxB        = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_AUX_B)
+	DeviceIDLightgunStart       = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_START)
+	DeviceIDLightgunSelect      = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_SELECT)
+	DeviceIDLightgunAuxC        = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_AUX_C)
+	DeviceIDLightgunDpadUp      = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_DPAD_UP)
+	DeviceIDLightgunDpadDown    = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_DPAD_DOWN)
+	DeviceIDLightgunDpadLeft    = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_DPAD_LEFT)
+	DeviceIDLightgunDpadRight   = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_DPAD_RIGHT)
+	DeviceIDLightgunX           = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_X) // Deprecated
+	DeviceIDLightgunY           = uint32(C.RETRO_DEVICE_ID_LIGHTGUN_Y) // Deprecated
+)
+
+// Id values for POINTER.
+const (
+	DeviceIDPointerX       = uint32(C.RETRO_DEVICE_ID_POINTER_X)
+	DeviceIDPointerY       = uint32(C.RETRO_DEVICE_ID_POINTER_Y)
+	DeviceIDPointerPressed = uint32(C.RETRO_DEVICE_ID_POINTER_PRESSED)
+	DeviceIDPointerCount   = uint32(C.RETRO_DEVICE_ID_POINTER_COUNT)
 )
 
 // Buttons for the RetroPad (JOYPAD).
@@ -304,6 +422,165 @@ const (
 	DeviceIDAnalogY         = uint32(C.RETRO_DEVICE_ID_ANALOG_Y)
 )
 
+// Keysyms used for ID in input state callback when polling the KEYBOARD
+// device, and passed to the keyboard callback.
+const (
+	KeyUnknown      = uint32(C.RETROK_UNKNOWN)
+	KeyBackspace    = uint32(C.RETROK_BACKSPACE)
+	KeyTab          = uint32(C.RETROK_TAB)
+	KeyClear        = uint32(C.RETROK_CLEAR)
+	KeyReturn       = uint32(C.RETROK_RETURN)
+	KeyPause        = uint32(C.RETROK_PAUSE)
+	KeyEscape       = uint32(C.RETROK_ESCAPE)
+	KeySpace        = uint32(C.RETROK_SPACE)
+	KeyExclaim      = uint32(C.RETROK_EXCLAIM)
+	KeyQuoteDbl     = uint32(C.RETROK_QUOTEDBL)
+	KeyHash         = uint32(C.RETROK_HASH)
+	KeyDollar       = uint32(C.RETROK_DOLLAR)
+	KeyAmpersand    = uint32(C.RETROK_AMPERSAND)
+	KeyQuote        = uint32(C.RETROK_QUOTE)
+	KeyLeftParen    = uint32(C.RETROK_LEFTPAREN)
+	KeyRightParen   = uint32(C.RETROK_RIGHTPAREN)
+	KeyAsterisk     = uint32(C.RETROK_ASTERISK)
+	KeyPlus         = uint32(C.RETROK_PLUS)
+	KeyComma        = uint32(C.RETROK_COMMA)
+	KeyMinus        = uint32(C.RETROK_MINUS)
+	KeyPeriod       = uint32(C.RETROK_PERIOD)
+	KeySlash        = uint32(C.RETROK_SLASH)
+	Key0            = uint32(C.RETROK_0)
+	Key1            = uint32(C.RETROK_1)
+	Key2            = uint32(C.RETROK_2)
+	Key3            = uint32(C.RETROK_3)
+	Key4            = uint32(C.RETROK_4)
+	Key5            = uint32(C.RETROK_5)
+	Key6            = uint32(C.RETROK_6)
+	Key7            = uint32(C.RETROK_7)
+	Key8            = uint32(C.RETROK_8)
+	Key9            = uint32(C.RETROK_9)
+	KeyColon        = uint32(C.RETROK_COLON)
+	KeySemicolon    = uint32(C.RETROK_SEMICOLON)
+	KeyLess         = uint32(C.RETROK_LESS)
+	KeyEquals       = uint32(C.RETROK_EQUALS)
+	KeyGreater      = uint32(C.RETROK_GREATER)
+	KeyQuestion     = uint32(C.RETROK_QUESTION)
+	KeyAt           = uint32(C.RETROK_AT)
+	KeyLeftBracket  = uint32(C.RETROK_LEFTBRACKET)
+	KeyBackslash    = uint32(C.RETROK_BACKSLASH)
+	KeyRightBracket = uint32(C.RETROK_RIGHTBRACKET)
+	KeyCaret        = uint32(C.RETROK_CARET)
+	KeyUnderscore   = uint32(C.RETROK_UNDERSCORE)
+	KeyBackquote    = uint32(C.RETROK_BACKQUOTE)
+	KeyA            = uint32(C.RETROK_a)
+	KeyB            = uint32(C.RETROK_b)
+	KeyC            = uint32(C.RETROK_c)
+	KeyD            = uint32(C.RETROK_d)
+	KeyE            = uint32(C.RETROK_e)
+	KeyF            = uint32(C.RETROK_f)
+	KeyG            = uint32(C.RETROK_g)
+	KeyH            = uint32(C.RETROK_h)
+	KeyI            = uint32(C.RETROK_i)
+	KeyJ            = uint32(C.RETROK_j)
+	KeyK            = uint32(C.RETROK_k)
+	KeyL            = uint32(C.RETROK_l)
+	KeyM            = uint32(C.RETROK_m)
+	KeyN            = uint32(C.RETROK_n)
+	KeyO            = uint32(C.RETROK_o)
+	KeyP            = uint32(C.RETROK_p)
+	KeyQ            = uint32(C.RETROK_q)
+	KeyR            = uint32(C.RETROK_r)
+	KeyS            = uint32(C.RETROK_s)
+	KeyT            = uint32(C.RETROK_t)
+	KeyU            = uint32(C.RETROK_u)
+	KeyV            = uint32(C.RETROK_v)
+	KeyW            = uint32(C.RETROK_w)
+	KeyX            = uint32(C.RETROK_x)
+	KeyY            = uint32(C.RETROK_y)
+	KeyZ            = uint32(C.RETROK_z)
+	KeyLeftBrace    = uint32(C.RETROK_LEFTBRACE)
+	KeyBar          = uint32(C.RETROK_BAR)
+	KeyRightBrace   = uint32(C.RETROK_RIGHTBRACE)
+	KeyTilde        = uint32(C.RETROK_TILDE)
+	KeyDelete       = uint32(C.RETROK_DELETE)
+	KeyKP0          = uint32(C.RETROK_KP0)
+	KeyKP1          = uint32(C.RETROK_KP1)
+	KeyKP2          = uint32(C.RETROK_KP2)
+	KeyKP3          = uint32(C.RETROK_KP3)
+	KeyKP4          = uint32(C.RETROK_KP4)
+	KeyKP5          = uint32(C.RETROK_KP5)
+	KeyKP6          = uint32(C.RETROK_KP6)
+	KeyKP7          = uint32(C.RETROK_KP7)
+	KeyKP8          = uint32(C.RETROK_KP8)
+	KeyKP9          = uint32(C.RETROK_KP9)
+	KeyKPPeriod     = uint32(C.RETROK_KP_PERIOD)
+	KeyKPDivide     = uint32(C.RETROK_KP_DIVIDE)
+	KeyKPMultiply   = uint32(C.RETROK_KP_MULTIPLY)
+	KeyKPMinus      = uint32(C.RETROK_KP_MINUS)
+	KeyKPPlus       = uint32(C.RETROK_KP_PLUS)
+	KeyKPEnter      = uint32(C.RETROK_KP_ENTER)
+	KeyKPEquals     = uint32(C.RETROK_KP_EQUALS)
+	KeyUp           = uint32(C.RETROK_UP)
+	KeyDown         = uint32(C.RETROK_DOWN)
+	KeyRight        = uint32(C.RETROK_RIGHT)
+	KeyLeft         = uint32(C.RETROK_LEFT)
+	KeyInsert       = uint32(C.RETROK_INSERT)
+	KeyHome         = uint32(C.RETROK_HOME)
+	KeyEnd          = uint32(C.RETROK_END)
+	KeyPageUp       = uint32(C.RETROK_PAGEUP)
+	KeyPageDown     = uint32(C.RETROK_PAGEDOWN)
+	KeyF1           = uint32(C.RETROK_F1)
+	KeyF2           = uint32(C.RETROK_F2)
+	KeyF3           = uint32(C.RETROK_F3)
+	KeyF4           = uint32(C.RETROK_F4)
+	KeyF5           = uint32(C.RETROK_F5)
+	KeyF6           = uint32(C.RETROK_F6)
+	KeyF7           = uint32(C.RETROK_F7)
+	KeyF8           = uint32(C.RETROK_F8)
+	KeyF9           = uint32(C.RETROK_F9)
+	KeyF10          = uint32(C.RETROK_F10)
+	KeyF11          = uint32(C.RETROK_F11)
+	KeyF12          = uint32(C.RETROK_F12)
+	KeyF13          = uint32(C.RETROK_F13)
+	KeyF14          = uint32(C.RETROK_F14)
+	KeyF15          = uint32(C.RETROK_F15)
+	KeyNumLock      = uint32(C.RETROK_NUMLOCK)
+	KeyCapsLock     = uint32(C.RETROK_CAPSLOCK)
+	KeyScrollLock   = uint32(C.RETROK_SCROLLOCK)
+	KeyRShift       = uint32(C.RETROK_RSHIFT)
+	KeyLShift       = uint32(C.RETROK_LSHIFT)
+	KeyRCtrl        = uint32(C.RETROK_RCTRL)
+	KeyLCtrl        = uint32(C.RETROK_LCTRL)
+	KeyRAlt         = uint32(C.RETROK_RALT)
+	KeyLAlt         = uint32(C.RETROK_LALT)
+	KeyRMeta        = uint32(C.RETROK_RMETA)
+	KeyLMeta        = uint32(C.RETROK_LMETA)
+	KeyLSuper       = uint32(C.RETROK_LSUPER)
+	KeyRSuper       = uint32(C.RETROK_RSUPER)
+	KeyMode         = uint32(C.RETROK_MODE)
+	KeyCompose      = uint32(C.RETROK_COMPOSE)
+	KeyHelp         = uint32(C.RETROK_HELP)
+	KeyPrint        = uint32(C.RETROK_PRINT)
+	KeySysReq       = uint32(C.RETROK_SYSREQ)
+	KeyBreak        = uint32(C.RETROK_BREAK)
+	KeyMenu         = uint32(C.RETROK_MENU)
+	KeyPower        = uint32(C.RETROK_POWER)
+	KeyEuro         = uint32(C.RETROK_EURO)
+	KeyUndo         = uint32(C.RETROK_UNDO)
+	KeyOEM102       = uint32(C.RETROK_OEM_102)
+	KeyLast         = uint32(C.RETROK_LAST)
+)
+
+// Keyboard modifiers passed to the keyboard callback.
+const (
+	KeyModNone       = uint16(C.RETROKMOD_NONE)
+	KeyModShift      = uint16(C.RETROKMOD_SHIFT)
+	KeyModCtrl       = uint16(C.RETROKMOD_CTRL)
+	KeyModAlt        = uint16(C.RETROKMOD_ALT)
+	KeyModMeta       = uint16(C.RETROKMOD_META)
+	KeyModNumLock    = uint16(C.RETROKMOD_NUMLOCK)
+	KeyModCapsLock   = uint16(C.RETROKMOD_CAPSLOCK)
+	KeyModScrollLock = uint16(C.RETROKMOD_SCROLLOCK)
+)
+
 // Environment callback API. See libretro.h for details
 const (
 	EnvironmentSetRotation                      = uint32(C.RETRO_ENVIRONMENT_SET_ROTATION)
@@ -382,6 +659,15 @@ const (
 	MemoryVideoRAM  = uint32(C.RETRO_MEMORY_VIDEO_RAM)
 )
 
+// Memory descriptor flags
+const (
+	MemDescConst     = uint64(C.RETRO_MEMDESC_CONST)
+	MemDescBigEndian = uint64(C.RETRO_MEMDESC_BIGENDIAN)
+	MemDescSystemRAM = uint64(C.RETRO_MEMDESC_SYSTEM_RAM)
+	MemDescSaveRAM   = uint64(C.RETRO_MEMDESC_SAVE_RAM)
+	MemDescVideoRAM  = uint64(C.RETRO_MEMDESC_VIDEO_RAM)
+)
+
 type (
 	environmentFunc      func(uint32, unsafe.Pointer) bool
 	videoRefreshFunc     func(unsafe.Pointer, int32, int32, int32)
@@ -391,6 +677,9 @@ type (
 	inputStateFunc       func(uint, uint32, uint, uint) int16
 	logFunc              func(uint32, string)
 	getTimeUsecFunc      func() int64
+	getCurrentFBFunc     func() uintptr
+	getProcAddressFunc   func(string) unsafe.Pointer
+	setRumbleStateFunc   func(uint, uint32, uint16) bool
 )
 
 var (
@@ -402,6 +691,9 @@ var (
 	inputState       inputStateFunc
 	log              logFunc
 	getTimeUsec      getTimeUsecFunc
+	getCurrentFB     getCurrentFBFunc
+	getProcAddress   getProcAddressFunc
+	setRumbleState   setRumbleStateFunc
 )
 
 // Load dynamically loads a libretro core at the given path and returns a Core instance
@@ -429,12 +721,15 @@ func Load(sofile string) (*Core, error) {
 	core.symRetroRun = core.DlSym("retro_run")
 	core.symRetroReset = core.DlSym("retro_reset")
 	core.symRetroLoadGame = core.DlSym("retro_load_game")
+	core.symRetroLoadGameSpecial = core.DlSym("retro_load_game_special")
 	core.symRetroUnloadGame = core.DlSym("retro_unload_game")
 	core.symRetroSerializeSize = core.DlSym("retro_serialize_size")
 	core.symRetroSerialize = core.DlSym("retro_serialize")
 	core.symRetroUnserialize = core.DlSym("retro_unserialize")
 	core.symRetroGetMemorySize = core.DlSym("retro_get_memory_size")
 	core.symRetroGetMemoryData = core.DlSym("retro_get_memory_data")
+	core.symRetroCheatReset = core.DlSym("retro_cheat_reset")
+	core.symRetroCheatSet = core.DlSym("retro_cheat_set")
 
 	return &core, nil
 }
@@ -461,6 +756,11 @@ func (core *Core) Deinit() {
 	inputState = nil
 	log = nil
 	getTimeUsec = nil
+	getCurrentFB = nil
+	getProcAddress = nil
+	setRumbleState = nil
+	vfs = nil
+	closeVFSHandles()
 }
 
 // Run runs the game for one video frame.
@@ -526,6 +826,30 @@ func (core *Core) LoadGame(gi GameInfo) bool {
 	return bool(C.bridge_retro_load_game(core.symRetroLoadGame, &rgi))
 }
 
+// LoadNoGame starts a core that supports running without a game
+func (core *Core) LoadNoGame() bool {
+	return bool(C.bridge_retro_load_game(core.symRetroLoadGame, nil))
+}
+
+// LoadGameSpecial loads a game of a subsystem, made of several roms. The game
+// infos are in the order of the roms of the subsystem.
+func (core *Core) LoadGameSpecial(id uint, gis []GameInfo) bool {
+	if core.symRetroLoadGameSpecial == nil || len(gis) == 0 {
+		return false
+	}
+	n := len(gis)
+	rgis := (*[1 << 16]C.struct_retro_game_info)(C.calloc(C.size_t(n), C.sizeof_struct_retro_game_info))[:n:n]
+	defer C.free(unsafe.Pointer(&rgis[0]))
+	for i, gi := range gis {
+		if gi.Path != "" {
+			rgis[i].path = C.CString(gi.Path)
+		}
+		rgis[i].size = C.size_t(gi.Size)
+		rgis[i].data = gi.Data
+	}
+	return bool(C.bridge_retro_load_game_special(core.symRetroLoadGameSpecial, C.unsigned(id), &rgis[0], C.size_t(n)))
+}
+
 // SerializeSize returns the amount of data the implementation requires to serialize
 // internal state (save states).
 // Between calls to retro_load_game() and retro_unload_game(), the
@@ -538,6 +862,7 @@ func (core *Core) SerializeSize() uint {
 // Serialize serializes internal state and returns the state as a byte slice.
 func (core *Core) Serialize(size uint) ([]byte, error) {
 	data := C.malloc(C.size_t(size))
+	defer C.free(data)
 	ok := bool(C.bridge_retro_serialize(core.symRetroSerialize, data, C.size_t(size)))
 	if !ok {
 		return nil, errors.New("retro_serialize failed")
@@ -619,6 +944,186 @@ func (core *Core) BindPerfCallback(data unsafe.Pointer, f getTimeUsecFunc) {
 	cb.get_time_usec = (C.retro_perf_get_time_usec_t)(C.coreGetTimeUsec_cgo)
 }
 
+// BindRumbleInterface binds f to the rumble interface set_rumble_state
+func (core *Core) BindRumbleInterface(data unsafe.Pointer, f setRumbleStateFunc) {
+	setRumbleState = f
+	cb := (*C.struct_retro_rumble_interface)(data)
+	cb.set_rumble_state = (C.retro_set_rumble_state_t)(C.coreSetRumbleState_cgo)
+}
+
+// VFS file access flags, see VFS.Open
+const (
+	VFSFileAccessRead           = uint(C.RETRO_VFS_FILE_ACCESS_READ)
+	VFSFileAccessWrite          = uint(C.RETRO_VFS_FILE_ACCESS_WRITE)
+	VFSFileAccessReadWrite      = uint(C.RETRO_VFS_FILE_ACCESS_READ_WRITE)
+	VFSFileAccessUpdateExisting = uint(C.RETRO_VFS_FILE_ACCESS_UPDATE_EXISTING)
+)
+
+// vfsVersion is the version of the VFS interface implemented by the frontend
+const vfsVersion = 3
+
+// VFSFile is a file opened by the core through the VFS interface
+type VFSFile interface {
+	io.ReadWriteSeeker
+	io.Closer
+	Stat() (os.FileInfo, error)
+	Truncate(size int64) error
+	Sync() error
+}
+
+// VFS is the file system the core accesses through the VFS interface
+type VFS interface {
+	// Open opens a file with the VFSFileAccess flags in mode
+	Open(path string, mode uint) (VFSFile, error)
+	Remove(path string) error
+	Rename(oldPath, newPath string) error
+	Stat(path string) (os.FileInfo, error)
+	Mkdir(path string) error
+	ReadDir(path string) ([]os.FileInfo, error)
+}
+
+// vfsFile is a file handle given to the core
+type vfsFile struct {
+	file VFSFile
+	path *C.char // returned by get_path, freed on close
+}
+
+// vfsDir is a directory handle given to the core
+type vfsDir struct {
+	entries []os.FileInfo
+	pos     int
+	name    *C.char // returned by dirent_get_name, freed on the next entry
+}
+
+// The handles given to the core are small C allocations used as keys, Go
+// pointers can't be kept by C code
+var (
+	vfs       VFS
+	vfsMutex  sync.Mutex
+	vfsFiles  = map[unsafe.Pointer]*vfsFile{}
+	vfsDirs   = map[unsafe.Pointer]*vfsDir{}
+	vfsStruct *C.struct_retro_vfs_interface
+)
+
+// SetVFSInterface is an environment callback helper to give the VFS interface
+// to the core. The file operations of the core are served by fs. It returns
+// false if the core requires a newer version of the interface.
+func (core *Core) SetVFSInterface(data unsafe.Pointer, fs VFS) bool {
+	info := (*C.struct_retro_vfs_interface_info)(data)
+	if info.required_interface_version > vfsVersion {
+		return false
+	}
+
+	if vfsStruct == nil {
+		vfsStruct = (*C.struct_retro_vfs_interface)(C.calloc(1, C.sizeof_struct_retro_vfs_interface))
+		vfsStruct.get_path = (C.retro_vfs_get_path_t)(C.coreVFSGetPath_cgo)
+		vfsStruct.open = (C.retro_vfs_open_t)(C.coreVFSOpen_cgo)
+		vfsStruct.close = (C.retro_vfs_close_t)(C.coreVFSClose_cgo)
+		vfsStruct.size = (C.retro_vfs_size_t)(C.coreVFSSize_cgo)
+		vfsStruct.truncate = (C.retro_vfs_truncate_t)(C.coreVFSTruncate_cgo)
+		vfsStruct.tell = (C.retro_vfs_tell_t)(C.coreVFSTell_cgo)
+		vfsStruct.seek = (C.retro_vfs_seek_t)(C.coreVFSSeek_cgo)
+		vfsStruct.read = (C.retro_vfs_read_t)(C.coreVFSRead_cgo)
+		vfsStruct.write = (C.retro_vfs_write_t)(C.coreVFSWrite_cgo)
+		vfsStruct.flush = (C.retro_vfs_flush_t)(C.coreVFSFlush_cgo)
+		vfsStruct.remove = (C.retro_vfs_remove_t)(C.coreVFSRemove_cgo)
+		vfsStruct.rename = (C.retro_vfs_rename_t)(C.coreVFSRename_cgo)
+		vfsStruct.stat = (C.retro_vfs_stat_t)(C.coreVFSStat_cgo)
+		vfsStruct.mkdir = (C.retro_vfs_mkdir_t)(C.coreVFSMkdir_cgo)
+		vfsStruct.opendir = (C.retro_vfs_opendir_t)(C.coreVFSOpendir_cgo)
+		vfsStruct.readdir = (C.retro_vfs_readdir_t)(C.coreVFSReaddir_cgo)
+		vfsStruct.dirent_get_name = (C.retro_vfs_dirent_get_name_t)(C.coreVFSDirentGetName_cgo)
+		vfsStruct.dirent_is_dir = (C.retro_vfs_dirent_is_dir_t)(C.coreVFSDirentIsDir_cgo)
+		vfsStruct.closedir = (C.retro_vfs_closedir_t)(C.coreVFSClosedir_cgo)
+	}
+
+	vfs = fs
+	core.UsesVFS = true
+	info.required_interface_version = vfsVersion
+	info.iface = vfsStruct
+	return true
+}
+
+// closeVFSHandles closes the files and directories left open by the core
+func closeVFSHandles() {
+	vfsMutex.Lock()
+	defer vfsMutex.Unlock()
+
+	for h, f := range vfsFiles {
+		f.file.Close()
+		C.free(unsafe.Pointer(f.path))
+		C.free(h)
+	}
+	for h, d := range vfsDirs {
+		C.free(unsafe.Pointer(d.name))
+		C.free(h)
+	}
+	vfsFiles = map[unsafe.Pointer]*vfsFile{}
+	vfsDirs = map[unsafe.Pointer]*vfsDir{}
+}
+
+// getVFSFile returns the file of a handle, or nil if the handle is unknown
+func getVFSFile(h unsafe.Pointer) *vfsFile {
+	vfsMutex.Lock()
+	defer vfsMutex.Unlock()
+	return vfsFiles[h]
+}
+
+// getVFSDir returns the directory of a handle, or nil if the handle is unknown
+func getVFSDir(h unsafe.Pointer) *vfsDir {
+	vfsMutex.Lock()
+	defer vfsMutex.Unlock()
+	return vfsDirs[h]
+}
+
+// vfsResult converts an error to the return value of the VFS functions
+func vfsResult(err error) C.int {
+	if err != nil {
+		return -1
+	}
+	return 0
+}
+
+// SetHWRenderCallback is an environment callback helper to store the hardware
+// rendering context requested by the core. It binds fb and proc to the
+// get_current_framebuffer and get_proc_address callbacks.
+func (core *Core) SetHWRenderCallback(data unsafe.Pointer, fb getCurrentFBFunc, proc getProcAddressFunc) {
+	getCurrentFB = fb
+	getProcAddress = proc
+	cb := (*C.struct_retro_hw_render_callback)(data)
+	cb.get_current_framebuffer = (C.retro_hw_get_current_framebuffer_t)(C.coreGetCurrentFramebuffer_cgo)
+	cb.get_proc_address = (C.retro_hw_get_proc_address_t)(C.coreGetProcAddress_cgo)
+
+	c := *cb
+	hw := &HWRenderCallback{
+		ContextType:      uint32(c.context_type),
+		Depth:            bool(c.depth),
+		Stencil:          bool(c.stencil),
+		BottomLeftOrigin: bool(c.bottom_left_origin),
+		VersionMajor:     uint(c.version_major),
+		VersionMinor:     uint(c.version_minor),
+		CacheContext:     bool(c.cache_context),
+	}
+	hw.ContextReset = func() {
+		if c.context_reset != nil {
+			C.bridge_retro_hw_context_reset(c.context_reset)
+		}
+	}
+	hw.ContextDestroy = func() {
+		if c.context_destroy != nil {
+			C.bridge_retro_hw_context_reset(c.context_destroy)
+		}
+	}
+	core.HWRenderCallback = hw
+}
+
+// IsHWFrameBufferValid tells if the data passed to the video refresh callback
+// is RETRO_HW_FRAME_BUFFER_VALID, meaning that the frame has been rendered in
+// the framebuffer of the hardware rendering context
+func IsHWFrameBufferValid(data unsafe.Pointer) bool {
+	return uintptr(data) == ^uintptr(0)
+}
+
 // SetControllerPortDevice sets the device type attached to a controller port
 func (core *Core) SetControllerPortDevice(port uint, device uint32) {
 	C.bridge_retro_set_controller_port_device(core.symRetroSetControllerPortDevice, C.unsigned(port), C.unsigned(device))
@@ -688,6 +1193,282 @@ func coreGetTimeUsec() C.uint64_t {
 	return C.uint64_t(getTimeUsec())
 }
 
+//export coreGetCurrentFramebuffer
+func coreGetCurrentFramebuffer() C.uintptr_t {
+	if getCurrentFB == nil {
+		return 0
+	}
+	return C.uintptr_t(getCurrentFB())
+}
+
+//export coreGetProcAddress
+func coreGetProcAddress(sym *C.char) unsafe.Pointer {
+	if getProcAddress == nil {
+		return nil
+	}
+	return getProcAddress(C.GoString(sym))
+}
+
+//export coreSetRumbleState
+func coreSetRumbleState(port C.unsigned, effect C.enum_retro_rumble_effect, strength C.uint16_t) C.bool {
+	if setRumbleState == nil {
+		return false
+	}
+	return C.bool(setRumbleState(uint(port), uint32(effect), uint16(strength)))
+}
+
+//export coreVFSGetPath
+func coreVFSGetPath(stream unsafe.Pointer) *C.char {
+	f := getVFSFile(stream)
+	if f == nil {
+		return nil
+	}
+	return f.path
+}
+
+//export coreVFSOpen
+func coreVFSOpen(path *C.char, mode C.unsigned, hints C.unsigned) unsafe.Pointer {
+	if vfs == nil {
+		return nil
+	}
+	file, err := vfs.Open(C.GoString(path), uint(mode))
+	if err != nil {
+		return nil
+	}
+
+	vfsMutex.Lock()
+	defer vfsMutex.Unlock()
+	h := C.malloc(1)
+	vfsFiles[h] = &vfsFile{file: file, path: C.CString(C.GoString(path))}
+	return h
+}
+
+//export coreVFSClose
+func coreVFSClose(stream unsafe.Pointer) C.int {
+	vfsMutex.Lock()
+	defer vfsMutex.Unlock()
+	f, ok := vfsFiles[stream]
+	if !ok {
+		return -1
+	}
+	delete(vfsFiles, stream)
+	err := f.file.Close()
+	C.free(unsafe.Pointer(f.path))
+	C.free(stream)
+	return vfsResult(err)
+}
+
+//export coreVFSSize
+func coreVFSSize(stream unsafe.Pointer) C.int64_t {
+	f := getVFSFile(stream)
+	if f == nil {
+		return -1
+	}
+	fi, err := f.file.Stat()
+	if err != nil {
+		return -1
+	}
+	return C.int64_t(fi.Size())
+}
+
+//export coreVFSTruncate
+func coreVFSTruncate(stream unsafe.Pointer, length C.int64_t) C.int64_t {
+	f := getVFSFile(stream)
+	if f == nil {
+		return -1
+	}
+	return C.int64_t(vfsResult(f.file.Truncate(int64(length))))
+}
+
+//export coreVFSTell
+func coreVFSTell(stream unsafe.Pointer) C.int64_t {
+	return coreVFSSeek(stream, 0, io.SeekCurrent)
+}
+
+//export coreVFSSeek
+func coreVFSSeek(stream unsafe.Pointer, offset C.int64_t, whence C.int) C.int64_t {
+	f := getVFSFile(stream)
+	if f == nil {
+		return -1
+	}
+	// The RETRO_VFS_SEEK_POSITION values are the same as the io.Seek ones
+	pos, err := f.file.Seek(int64(offset), int(whence))
+	if err != nil {
+		return -1
+	}
+	return C.int64_t(pos)
+}
+
+// vfsMaxChunk is the most bytes read or written by a single VFS call. Bigger
+// calls return a short count, and the core calls again for the rest.
+const vfsMaxChunk = 1 << 30
+
+//export coreVFSRead
+func coreVFSRead(stream unsafe.Pointer, s unsafe.Pointer, length C.uint64_t) C.int64_t {
+	f := getVFSFile(stream)
+	if f == nil {
+		return -1
+	}
+	if length == 0 {
+		return 0
+	}
+	if length > vfsMaxChunk {
+		length = vfsMaxChunk
+	}
+	buf := (*[1 << 30]byte)(s)[:length:length]
+	n, err := io.ReadFull(f.file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return -1
+	}
+	return C.int64_t(n)
+}
+
+//export coreVFSWrite
+func coreVFSWrite(stream unsafe.Pointer, s unsafe.Pointer, length C.uint64_t) C.int64_t {
+	f := getVFSFile(stream)
+	if f == nil {
+		return -1
+	}
+	if length > vfsMaxChunk {
+		length = vfsMaxChunk
+	}
+	n, err := f.file.Write(C.GoBytes(s, C.int(length)))
+	if err != nil {
+		return -1
+	}
+	return C.int64_t(n)
+}
+
+//export coreVFSFlush
+func coreVFSFlush(stream unsafe.Pointer) C.int {
+	f := getVFSFile(stream)
+	if f == nil {
+		return -1
+	}
+	return vfsResult(f.file.Sync())
+}
+
+//export coreVFSRemove
+func coreVFSRemove(path *C.char) C.int {
+	if vfs == nil {
+		return -1
+	}
+	return vfsResult(vfs.Remove(C.GoString(path)))
+}
+
+//export coreVFSRename
+func coreVFSRename(oldPath, newPath *C.char) C.int {
+	if vfs == nil {
+		return -1
+	}
+	return vfsResult(vfs.Rename(C.GoString(oldPath), C.GoString(newPath)))
+}
+
+//export coreVFSStat
+func coreVFSStat(path *C.char, size *C.int32_t) C.int {
+	if vfs == nil {
+		return 0
+	}
+	fi, err := vfs.Stat(C.GoString(path))
+	if err != nil {
+		return 0
+	}
+	flags := C.int(C.RETRO_VFS_STAT_IS_VALID)
+	if fi.IsDir() {
+		flags |= C.RETRO_VFS_STAT_IS_DIRECTORY
+	}
+	if fi.Mode()&os.ModeCharDevice != 0 {
+		flags |= C.RETRO_VFS_STAT_IS_CHARACTER_SPECIAL
+	}
+	if size != nil {
+		*size = C.int32_t(fi.Size())
+	}
+	return flags
+}
+
+//export coreVFSMkdir
+func coreVFSMkdir(dir *C.char) C.int {
+	if vfs == nil {
+		return -1
+	}
+	err := vfs.Mkdir(C.GoString(dir))
+	if os.IsExist(err) {
+		return -2
+	}
+	return vfsResult(err)
+}
+
+//export coreVFSOpendir
+func coreVFSOpendir(dir *C.char, includeHidden C.bool) unsafe.Pointer {
+	if vfs == nil {
+		return nil
+	}
+	infos, err := vfs.ReadDir(C.GoString(dir))
+	if err != nil {
+		return nil
+	}
+	entries := []os.FileInfo{}
+	for _, fi := range infos {
+		if !bool(includeHidden) && strings.HasPrefix(fi.Name(), ".") {
+			continue
+		}
+		entries = append(entries, fi)
+	}
+
+	vfsMutex.Lock()
+	defer vfsMutex.Unlock()
+	h := C.malloc(1)
+	vfsDirs[h] = &vfsDir{entries: entries, pos: -1}
+	return h
+}
+
+//export coreVFSReaddir
+func coreVFSReaddir(dirstream unsafe.Pointer) C.bool {
+	d := getVFSDir(dirstream)
+	if d == nil || d.pos >= len(d.entries) {
+		return false
+	}
+	d.pos++
+	C.free(unsafe.Pointer(d.name))
+	d.name = nil
+	return d.pos < len(d.entries)
+}
+
+//export coreVFSDirentGetName
+func coreVFSDirentGetName(dirstream unsafe.Pointer) *C.char {
+	d := getVFSDir(dirstream)
+	if d == nil || d.pos < 0 || d.pos >= len(d.entries) {
+		return nil
+	}
+	if d.name == nil {
+		d.name = C.CString(d.entries[d.pos].Name())
+	}
+	return d.name
+}
+
+//export coreVFSDirentIsDir
+func coreVFSDirentIsDir(dirstream unsafe.Pointer) C.bool {
+	d := getVFSDir(dirstream)
+	if d == nil || d.pos < 0 || d.pos >= len(d.entries) {
+		return false
+	}
+	return C.bool(d.entries[d.pos].IsDir())
+}
+
+//export coreVFSClosedir
+func coreVFSClosedir(dirstream unsafe.Pointer) C.int {
+	vfsMutex.Lock()
+	defer vfsMutex.Unlock()
+	d, ok := vfsDirs[dirstream]
+	if !ok {
+		return -1
+	}
+	delete(vfsDirs, dirstream)
+	C.free(unsafe.Pointer(d.name))
+	C.free(dirstream)
+	return 0
+}
+
 // SetData is a setter for the data of a GameInfo type
 func (gi *GameInfo) SetData(bytes []byte) {
 	cstr := C.CString(string(bytes))
@@ -805,6 +1586,11 @@ func SetBool(data unsafe.Pointer, val bool) {
 	*b = C.bool(val)
 }
 
+// GetBool is an environment callback helper to read a boolean
+func GetBool(data unsafe.Pointer) bool {
+	return bool(*(*C.bool)(data))
+}
+
 // SetString is an environment callback helper to set a string
 func SetString(data unsafe.Pointer, val string) {
 	s := (**C.char)(data)
@@ -841,6 +1627,17 @@ func (core *Core) SetAudioCallback(data unsafe.Pointer) {
 	core.AudioCallback = auc
 }
 
+// SetKeyboardCallback is an environment callback helper to set the
+// KeyboardCallback
+func (core *Core) SetKeyboardCallback(data unsafe.Pointer) {
+	c := *(*C.struct_retro_keyboard_callback)(data)
+	kbc := &KeyboardCallback{}
+	kbc.Callback = func(down bool, keycode uint32, character uint32, modifiers uint16) {
+		C.bridge_retro_keyboard_event(c.callback, C.bool(down), C.unsigned(keycode), C.uint32_t(character), C.uint16_t(modifiers))
+	}
+	core.KeyboardCallback = kbc
+}
+
 // GetMemorySize returns the size of a region of the memory.
 // See memory constants.
 func (core *Core) GetMemorySize(id uint32) uint {
@@ -853,6 +1650,188 @@ func (core *Core) GetMemoryData(id uint32) unsafe.Pointer {
 	return C.bridge_retro_get_memory_data(core.symRetroGetMemoryData, C.unsigned(id))
 }
 
+// CheatReset disables all the cheats of the core
+func (core *Core) CheatReset() {
+	C.bridge_retro_cheat_reset(core.symRetroCheatReset)
+}
+
+// CheatSet enables or disables a cheat code at the given index
+func (core *Core) CheatSet(index uint, enabled bool, code string) {
+	ccode := C.CString(code)
+	defer C.free(unsafe.Pointer(ccode))
+	C.bridge_retro_cheat_set(core.symRetroCheatSet, C.unsigned(index), C.bool(enabled), ccode)
+}
+
+// MemoryDescriptor describes how a region of the emulated address space maps
+// to the memory of the core
+type MemoryDescriptor struct {
+	Flags      uint64
+	Ptr        unsafe.Pointer
+	Offset     uint
+	Start      uint
+	Select     uint
+	Disconnect uint
+	Len        uint
+	AddrSpace  string
+}
+
+// SetMemoryMaps is an environment callback helper to store the memory map
+// exposed by the core
+func (core *Core) SetMemoryMaps(data unsafe.Pointer) {
+	mmap := (*C.struct_retro_memory_map)(data)
+	descs := (*[1 << 16]C.struct_retro_memory_descriptor)(unsafe.Pointer(mmap.descriptors))[:mmap.num_descriptors:mmap.num_descriptors]
+
+	core.MemoryMap = nil
+	for _, d := range descs {
+		core.MemoryMap = append(core.MemoryMap, MemoryDescriptor{
+			Flags:      uint64(d.flags),
+			Ptr:        d.ptr,
+			Offset:     uint(d.offset),
+			Start:      uint(d.start),
+			Select:     uint(d._select),
+			Disconnect: uint(d.disconnect),
+			Len:        uint(d.len),
+			AddrSpace:  C.GoString(d.addrspace),
+		})
+	}
+}
+
+// InputDescriptor describes what an input does in the game, like "Jump"
+type InputDescriptor struct {
+	Port        uint
+	Device      uint32
+	Index       uint
+	ID          uint
+	Description string
+}
+
+// maxInputDescriptors bounds the scan of the input descriptors. It leaves room
+// for every button, axis and pointer of 16 ports.
+const maxInputDescriptors = 16 * 64
+
+// validInputDescriptor tells if the fields of an input descriptor are in the
+// ranges of the libretro API. The array of descriptors has to end with an
+// entry without description, but some cores forget it, like VecX 1.2 whose
+// 17th entry is garbage. The description of an invalid entry is not read.
+func validInputDescriptor(d C.struct_retro_input_descriptor) bool {
+	return d.port < 16 &&
+		uint32(d.device)&DeviceMask <= DevicePointer &&
+		uint32(d.index) <= DeviceIndexAnalogButton &&
+		uint32(d.id) <= DeviceIDJoypadMask
+}
+
+// SetInputDescriptors is an environment callback helper to store the input
+// descriptors exposed by the core
+func (core *Core) SetInputDescriptors(data unsafe.Pointer) {
+	descs := (*[maxInputDescriptors]C.struct_retro_input_descriptor)(data)
+
+	core.InputDescriptors = nil
+	for i := 0; i < maxInputDescriptors && descs[i].description != nil; i++ {
+		d := descs[i]
+		if !validInputDescriptor(d) {
+			break
+		}
+		core.InputDescriptors = append(core.InputDescriptors, InputDescriptor{
+			Port:        uint(d.port),
+			Device:      uint32(d.device),
+			Index:       uint(d.index),
+			ID:          uint(d.id),
+			Description: C.GoString(d.description),
+		})
+	}
+}
+
+// ControllerDescription is a controller type supported by the core
+type ControllerDescription struct {
+	Desc string
+	ID   uint32
+}
+
+// SetControllerInfo is an environment callback helper to store the controller
+// types supported by the core for each port
+func (core *Core) SetControllerInfo(data unsafe.Pointer) {
+	infos := (*[1 << 16]C.struct_retro_controller_info)(data)
+
+	core.ControllerInfo = nil
+	for i := 0; infos[i].types != nil; i++ {
+		n := infos[i].num_types
+		types := (*[1 << 16]C.struct_retro_controller_description)(unsafe.Pointer(infos[i].types))[:n:n]
+		port := []ControllerDescription{}
+		for _, t := range types {
+			port = append(port, ControllerDescription{
+				Desc: C.GoString(t.desc),
+				ID:   uint32(t.id),
+			})
+		}
+		core.ControllerInfo = append(core.ControllerInfo, port)
+	}
+}
+
+// SubsystemMemory is a persistent memory of a subsystem rom, like the SRAM of
+// the cartridge. It is saved to a file with the given extension.
+type SubsystemMemory struct {
+	Extension string
+	Type      uint32
+}
+
+// SubsystemRom describes a rom slot of a subsystem
+type SubsystemRom struct {
+	Desc            string
+	ValidExtensions string
+	NeedFullpath    bool
+	BlockExtract    bool
+	Required        bool
+	Memory          []SubsystemMemory
+}
+
+// SubsystemInfo describes a special game type, loaded with
+// retro_load_game_special, like a Super Game Boy game that needs a BIOS and a
+// Game Boy rom
+type SubsystemInfo struct {
+	Desc  string
+	Ident string
+	ID    uint
+	Roms  []SubsystemRom
+}
+
+// SetSubsystemInfo is an environment callback helper to store the subsystems
+// supported by the core
+func (core *Core) SetSubsystemInfo(data unsafe.Pointer) {
+	infos := (*[1 << 16]C.struct_retro_subsystem_info)(data)
+
+	core.SubsystemInfo = nil
+	for i := 0; infos[i].desc != nil; i++ {
+		n := infos[i].num_roms
+		roms := (*[1 << 16]C.struct_retro_subsystem_rom_info)(unsafe.Pointer(infos[i].roms))[:n:n]
+		si := SubsystemInfo{
+			Desc:  C.GoString(infos[i].desc),
+			Ident: C.GoString(infos[i].ident),
+			ID:    uint(infos[i].id),
+		}
+		for _, r := range roms {
+			rom := SubsystemRom{
+				Desc:            C.GoString(r.desc),
+				ValidExtensions: C.GoString(r.valid_extensions),
+				NeedFullpath:    bool(r.need_fullpath),
+				BlockExtract:    bool(r.block_extract),
+				Required:        bool(r.required),
+			}
+			m := r.num_memory
+			if m > 0 {
+				mems := (*[1 << 16]C.struct_retro_subsystem_memory_info)(unsafe.Pointer(r.memory))[:m:m]
+				for _, mem := range mems {
+					rom.Memory = append(rom.Memory, SubsystemMemory{
+						Extension: C.GoString(mem.extension),
+						Type:      uint32(mem._type),
+					})
+				}
+			}
+			si.Roms = append(si.Roms, rom)
+		}
+		core.SubsystemInfo = append(core.SubsystemInfo, si)
+	}
+}
+
 // DiskControlCallback is an interface which frontend can use to eject and insert disk images
 type DiskControlCallback struct {
 	SetEjectState func(bool)
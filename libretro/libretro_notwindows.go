@@ -33,16 +33,27 @@ type Core struct {
 	symRetroRun                     unsafe.Pointer
 	symRetroReset                   unsafe.Pointer
 	symRetroLoadGame                unsafe.Pointer
+	symRetroLoadGameSpecial         unsafe.Pointer
 	symRetroUnloadGame              unsafe.Pointer
 	symRetroSerializeSize           unsafe.Pointer
 	symRetroSerialize               unsafe.Pointer
 	symRetroUnserialize             unsafe.Pointer
 	symRetroGetMemorySize           unsafe.Pointer
 	symRetroGetMemoryData           unsafe.Pointer
+	symRetroCheatReset              unsafe.Pointer
+	symRetroCheatSet                unsafe.Pointer
 
 	AudioCallback       *AudioCallback
 	FrameTimeCallback   *FrameTimeCallback
+	KeyboardCallback    *KeyboardCallback
 	DiskControlCallback *DiskControlCallback
+	HWRenderCallback    *HWRenderCallback
+	MemoryMap           []MemoryDescriptor
+	InputDescriptors    []InputDescriptor
+	ControllerInfo      [][]ControllerDescription // supported controller types, per port
+	SubsystemInfo       []SubsystemInfo           // special game types, like Super Game Boy
+	SupportNoGame       bool                      // the core can run without a game
+	UsesVFS             bool                      // the core accesses files through the VFS interface
 }
 
 // DlSym loads a symbol from a dynamic library
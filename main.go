@@ -2,21 +2,29 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"runtime"
 	"time"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/libretro/ludo/achievements"
 	"github.com/libretro/ludo/audio"
+	"github.com/libretro/ludo/cheats"
 	"github.com/libretro/ludo/core"
+	"github.com/libretro/ludo/headless"
 	"github.com/libretro/ludo/history"
 	"github.com/libretro/ludo/input"
 	"github.com/libretro/ludo/menu"
+	"github.com/libretro/ludo/movie"
 	ntf "github.com/libretro/ludo/notifications"
 	"github.com/libretro/ludo/playlists"
+	"github.com/libretro/ludo/rewind"
 	"github.com/libretro/ludo/savefiles"
+	"github.com/libretro/ludo/savestates"
 	"github.com/libretro/ludo/scanner"
+	"github.com/libretro/ludo/scheduler"
 	"github.com/libretro/ludo/settings"
 	"github.com/libretro/ludo/state"
 	"github.com/libretro/ludo/video"
@@ -37,18 +45,16 @@ func runLoop(vid *video.Video, m *menu.Menu) {
 		dt := float32(currTime.Sub(prevTime)) / 1000000000
 		glfw.PollEvents()
 		m.ProcessHotkeys()
+		input.ResetEdges()
 		ntf.Process(dt)
 		vid.ResizeViewport()
 		m.UpdatePalette()
 		if !state.MenuActive {
 			if state.CoreRunning {
-				state.Core.Run()
-				if state.Core.FrameTimeCallback != nil {
-					state.Core.FrameTimeCallback.Callback(state.Core.FrameTimeCallback.Reference)
-				}
-				if state.Core.AudioCallback != nil {
-					state.Core.AudioCallback.Callback()
+				for n := scheduler.Due(currTime, vid.RefreshRate()); n > 0; n-- {
+					runFrame()
 				}
+				savestates.Frame(dt)
 			}
 			vid.Render()
 			frame++
@@ -56,22 +62,64 @@ func runLoop(vid *video.Video, m *menu.Menu) {
 				savefiles.SaveSRAM()
 			}
 		} else {
+			scheduler.Reset()
 			input.Poll()
 			m.Update(dt)
 			vid.Render()
 			m.Render(dt)
 		}
 		m.RenderNotifications()
-		if state.FastForward {
-			glfw.SwapInterval(0)
-		} else {
-			glfw.SwapInterval(1)
-		}
+		interval := scheduler.SwapInterval()
+		glfw.SwapInterval(interval)
 		vid.Window.SwapBuffers()
+		if interval == 0 {
+			time.Sleep(scheduler.Wait(time.Now()))
+		}
 		prevTime = currTime
 	}
 }
 
+// runFrame runs one frame of the core, with the features that work frame by
+// frame
+func runFrame() {
+	if input.NewState[0][input.ActionRewind] == 1 && !movie.Active() {
+		if err := rewind.Pop(); err != nil && state.Verbose {
+			log.Println("[Rewind]:", err)
+		}
+	} else if err := rewind.Push(); err != nil && state.Verbose {
+		log.Println("[Rewind]:", err)
+	}
+	state.Core.Run()
+	if state.Core.FrameTimeCallback != nil {
+		state.Core.FrameTimeCallback.Callback(state.Core.FrameTimeCallback.Reference)
+	}
+	if state.Core.AudioCallback != nil {
+		state.Core.AudioCallback.Callback()
+	}
+	cheats.Frame()
+	achievements.Frame()
+	if err := movie.Frame(); err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Movie", err.Error())
+	}
+}
+
+// runHeadless runs the game for a fixed number of frames and prints a hash for
+// each frame, so the output can be compared between two builds of a core.
+func runHeadless(gamePath string, frames int, dump string) {
+	res, err := headless.Run(state.CorePath, gamePath, headless.Options{
+		Frames: frames,
+		Dump:   dump,
+	})
+	if err != nil {
+		log.Fatalln("[Headless]:", err)
+	}
+
+	for i, h := range res.Hashes {
+		fmt.Printf("frame %d: %08x\n", i, h)
+	}
+	fmt.Printf("audio: %d frames %08x\n", res.AudioFrames, res.AudioHash)
+}
+
 func main() {
 	err := settings.Load()
 	if err != nil {
@@ -83,6 +131,10 @@ func main() {
 	flag.StringVar(&state.CorePath, "L", "", "Path to the libretro core")
 	flag.BoolVar(&state.Verbose, "v", false, "Verbose logs")
 	flag.BoolVar(&state.LudOS, "ludos", false, "Expose the features related to LudOS")
+	flag.BoolVar(&state.Headless, "headless", false, "Run the game without window, audio or inputs, and print frame hashes")
+	frames := flag.Int("frames", 600, "Number of frames to run in headless mode")
+	dump := flag.String("dump", "", "Directory where to dump the frames as PNG in headless mode")
+	audioDriver := flag.String("audio", settings.Current.AudioDriver, "Audio driver: OpenAL, Null or WAV")
 	flag.Parse()
 	args := flag.Args()
 
@@ -91,6 +143,11 @@ func main() {
 		gamePath = args[0]
 	}
 
+	if state.Headless {
+		runHeadless(gamePath, *frames, *dump)
+		return
+	}
+
 	if err := glfw.Init(); err != nil {
 		log.Fatalln("Failed to initialize glfw", err)
 	}
@@ -107,13 +164,16 @@ func main() {
 
 	vid := video.Init(settings.Current.VideoFullscreen)
 
-	audio.Init()
+	audio.Init(*audioDriver)
 
 	m := menu.Init(vid)
 
 	core.Init(vid)
 
 	input.Init(vid)
+	if err := input.LoadBinds("", ""); err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Input", "Could not load remapping: %s", err)
+	}
 
 	if len(state.CorePath) > 0 {
 		err := core.Load(state.CorePath)
@@ -5,6 +5,7 @@ import (
 	"github.com/libretro/ludo/input"
 	"github.com/libretro/ludo/libretro"
 	ntf "github.com/libretro/ludo/notifications"
+	"github.com/libretro/ludo/savestates"
 	"github.com/libretro/ludo/settings"
 	"github.com/libretro/ludo/state"
 )
@@ -168,6 +169,11 @@ func (m *Menu) ProcessHotkeys() {
 		return
 	}
 
+	// Disable all hot keys while rebinding an input
+	if s, ok := currentScene.(*sceneRemap); ok && s.capturing {
+		return
+	}
+
 	// First menu combo
 	if input.NewState[0][libretro.DeviceIDJoypadL3] == 1 && input.NewState[0][libretro.DeviceIDJoypadR3] == 1 {
 		combo1++
@@ -187,6 +193,7 @@ func (m *Menu) ProcessHotkeys() {
 		state.MenuActive = !state.MenuActive
 		state.FastForward = false
 		if state.MenuActive {
+			input.StopRumble()
 			audio.PlayEffect(audio.Effects["notice"])
 		} else {
 			audio.PlayEffect(audio.Effects["notice_back"])
@@ -213,6 +220,23 @@ func (m *Menu) ProcessHotkeys() {
 		}
 	}
 
+	if input.Pressed[0][input.ActionGameFocusToggle] == 1 && state.CoreRunning && !state.MenuActive {
+		state.GameFocus = !state.GameFocus
+		if state.GameFocus {
+			ntf.DisplayAndLog(ntf.Info, "Menu", "Game focus ON")
+		} else {
+			ntf.DisplayAndLog(ntf.Info, "Menu", "Game focus OFF")
+		}
+	}
+
+	if input.Pressed[0][input.ActionRecordToggle] == 1 && state.CoreRunning {
+		toggleRecording()
+	}
+
+	if state.CoreRunning && !state.MenuActive {
+		m.processStateHotkeys()
+	}
+
 	// Close if ActionShouldClose is pressed, but display a confirmation dialog
 	// in case a game is running
 	if input.Pressed[0][input.ActionShouldClose] == 1 {
@@ -221,3 +245,38 @@ func (m *Menu) ProcessHotkeys() {
 		})
 	}
 }
+
+// processStateHotkeys saves and loads the quick save slots
+func (m *Menu) processStateHotkeys() {
+	slot := savestates.Slot()
+
+	if input.Pressed[0][input.ActionSaveState] == 1 {
+		name := savestates.SlotName(slot)
+		if err := m.TakeScreenshot(name); err != nil {
+			ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
+		}
+		if err := savestates.Save(name); err != nil {
+			ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
+		} else {
+			ntf.DisplayAndLog(ntf.Success, "Menu", "State saved to slot %d.", slot)
+		}
+	}
+
+	if input.Pressed[0][input.ActionLoadState] == 1 {
+		if err := savestates.LoadSlot(); err != nil {
+			ntf.DisplayAndLog(ntf.Error, "Menu", "Could not load slot %d: %s", slot, err)
+		} else {
+			ntf.DisplayAndLog(ntf.Success, "Menu", "State loaded from slot %d.", slot)
+		}
+	}
+
+	if input.Pressed[0][input.ActionStateSlotPrev] == 1 {
+		savestates.SetSlot(slot - 1)
+		ntf.DisplayAndLog(ntf.Info, "Menu", "State slot %d.", savestates.Slot())
+	}
+
+	if input.Pressed[0][input.ActionStateSlotNext] == 1 {
+		savestates.SetSlot(slot + 1)
+		ntf.DisplayAndLog(ntf.Info, "Menu", "State slot %d.", savestates.Slot())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/libretro/ludo/libretro"
 	"github.com/libretro/ludo/state"
 	"github.com/libretro/ludo/video"
 
@@ -233,3 +234,83 @@ func TestExtractTags(t *testing.T) {
 		})
 	}
 }
+
+func Test_inputLabels(t *testing.T) {
+	descs := []libretro.InputDescriptor{
+		{Port: 0, Device: libretro.DeviceJoypad, ID: uint(libretro.DeviceIDJoypadB), Description: "Jump"},
+		{Port: 0, Device: libretro.DeviceAnalog, Index: uint(libretro.DeviceIndexAnalogLeft), ID: uint(libretro.DeviceIDAnalogX), Description: "Steer"},
+		{Port: 0, Device: libretro.DeviceMouse, ID: 0, Description: "Aim"},
+		{Port: 1, Device: libretro.DeviceJoypad, ID: uint(libretro.DeviceIDJoypadStart), Description: "Pause"},
+		{Port: 0, Device: libretro.DeviceJoypad, ID: uint(libretro.DeviceIDJoypadL2), Description: ""},
+	}
+
+	t.Run("Describes the RetroPad of a port", func(t *testing.T) {
+		got := inputLabels(descs, 0)
+		want := []string{"B = Jump", "Left Analog X = Steer"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Filters by port", func(t *testing.T) {
+		got := inputLabels(descs, 1)
+		want := []string{"Start = Pause"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got = %v, want %v", got, want)
+		}
+	})
+}
+
+func Test_romExtensions(t *testing.T) {
+	tests := []struct {
+		name string
+		rom  libretro.SubsystemRom
+		want []string
+	}{
+		{
+			name: "Lists both cases and archives",
+			rom:  libretro.SubsystemRom{ValidExtensions: "gb|gbc"},
+			want: []string{".gb", ".GB", ".gbc", ".GBC", ".zip", ".7z"},
+		},
+		{
+			name: "Skips archives when extraction is blocked",
+			rom:  libretro.SubsystemRom{ValidExtensions: "sfc", BlockExtract: true},
+			want: []string{".sfc", ".SFC"},
+		},
+		{
+			name: "Allows all files without extensions",
+			rom:  libretro.SubsystemRom{},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := romExtensions(tt.rom)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_customAspectIncr(t *testing.T) {
+	tests := []struct {
+		name      string
+		v         float32
+		direction int
+		want      float32
+	}{
+		{"Increments", 1.33, 1, 1.38},
+		{"Decrements", 1.35, -1, 1.3},
+		{"Stays on hundredths", 0.55, -1, 0.5},
+		{"Stops at the minimum", 0.5, -1, 0.5},
+		{"Stops at the maximum", 3, 1, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := customAspectIncr(tt.v, tt.direction); got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
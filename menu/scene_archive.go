@@ -0,0 +1,62 @@
+package menu
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/libretro/ludo/vfs"
+)
+
+type sceneArchive struct {
+	entry
+}
+
+// buildArchive lets the user pick the game to load among the roms of an
+// archive
+func buildArchive(path string, roms []string) Scene {
+	var list sceneArchive
+	list.label = filepath.Base(path)
+
+	for _, rom := range roms {
+		rom := rom
+		list.children = append(list.children, entry{
+			label: strings.Replace(rom, "%", "%%", -1),
+			icon:  "file",
+			callbackOK: func() {
+				gameExplorerCb(path + vfs.Separator + rom)
+			},
+		})
+	}
+
+	list.segueMount()
+
+	return &list
+}
+
+func (s *sceneArchive) Entry() *entry {
+	return &s.entry
+}
+
+func (s *sceneArchive) segueMount() {
+	genericSegueMount(&s.entry)
+}
+
+func (s *sceneArchive) segueNext() {
+	genericSegueNext(&s.entry)
+}
+
+func (s *sceneArchive) segueBack() {
+	genericAnimate(&s.entry)
+}
+
+func (s *sceneArchive) update(dt float32) {
+	genericInput(&s.entry, dt)
+}
+
+func (s *sceneArchive) render() {
+	genericRender(&s.entry)
+}
+
+func (s *sceneArchive) drawHintBar() {
+	genericDrawHintBar()
+}
@@ -0,0 +1,80 @@
+package menu
+
+import (
+	"github.com/libretro/ludo/cheats"
+	"github.com/libretro/ludo/state"
+)
+
+type sceneCheats struct {
+	entry
+}
+
+func buildCheats() Scene {
+	var list sceneCheats
+	list.label = "Cheats"
+
+	for i, c := range cheats.List() {
+		i := i
+		list.children = append(list.children, entry{
+			label: c.Desc,
+			icon:  "subsetting",
+			value: func() interface{} {
+				return cheats.List()[i].Enabled
+			},
+			widget: widgets["switch"],
+			callbackOK: func() {
+				cheats.Toggle(i)
+			},
+		})
+	}
+
+	if len(list.children) == 0 {
+		list.children = append(list.children, entry{
+			label: "No cheat",
+			icon:  "subsetting",
+		})
+	}
+
+	list.segueMount()
+
+	return &list
+}
+
+func (s *sceneCheats) Entry() *entry {
+	return &s.entry
+}
+
+func (s *sceneCheats) segueMount() {
+	genericSegueMount(&s.entry)
+}
+
+func (s *sceneCheats) segueNext() {
+	genericSegueNext(&s.entry)
+}
+
+func (s *sceneCheats) segueBack() {
+	genericAnimate(&s.entry)
+}
+
+func (s *sceneCheats) update(dt float32) {
+	genericInput(&s.entry, dt)
+}
+
+func (s *sceneCheats) render() {
+	genericRender(&s.entry)
+}
+
+func (s *sceneCheats) drawHintBar() {
+	w, h := menu.GetFramebufferSize()
+	menu.DrawRect(0, float32(h)-70*menu.ratio, float32(w), 70*menu.ratio, 0, lightGrey)
+
+	_, upDown, _, a, b, _, _, _, _, guide := hintIcons()
+
+	var stack float32
+	if state.CoreRunning {
+		stackHint(&stack, guide, "RESUME", h)
+	}
+	stackHint(&stack, upDown, "NAVIGATE", h)
+	stackHint(&stack, b, "BACK", h)
+	stackHint(&stack, a, "TOGGLE", h)
+}
@@ -0,0 +1,176 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/libretro/ludo/input"
+	"github.com/libretro/ludo/libretro"
+	ntf "github.com/libretro/ludo/notifications"
+	"github.com/libretro/ludo/state"
+)
+
+var deviceLabels = map[uint32]string{
+	libretro.DeviceNone:     "None",
+	libretro.DeviceJoypad:   "RetroPad",
+	libretro.DeviceAnalog:   "RetroPad With Analog",
+	libretro.DeviceMouse:    "Mouse",
+	libretro.DevicePointer:  "Pointer",
+	libretro.DeviceLightgun: "Lightgun",
+}
+
+var analogLabels = [3]string{"Left Analog", "Right Analog", "Analog Button"}
+
+type sceneControls struct {
+	entry
+}
+
+func buildControls() Scene {
+	var list sceneControls
+	list.label = "Controls"
+
+	ports := input.MaxPlayers
+	if n := len(state.Core.ControllerInfo); n > 0 && n < ports {
+		ports = n
+	}
+
+	for port := uint(0); port < uint(ports); port++ {
+		port := port
+		list.children = append(list.children, entry{
+			label: fmt.Sprintf("Port %d Controller", port+1),
+			icon:  "subsetting",
+			stringValue: func() string {
+				label := controllerLabel(port, input.PortDevice(port))
+				return strings.Replace(label, "%", "%%", -1)
+			},
+			incr: func(direction int) {
+				setPortDevice(port, direction)
+			},
+		})
+
+		for _, label := range inputLabels(state.Core.InputDescriptors, port) {
+			list.children = append(list.children, entry{
+				label: strings.Replace(label, "%", "%%", -1),
+				icon:  "subsetting",
+			})
+		}
+	}
+
+	list.segueMount()
+
+	return &list
+}
+
+// controllers returns the controller types that can be plugged in a port, as
+// advertised by the core, or the generic devices if the core doesn't tell
+func controllers(port uint) []libretro.ControllerDescription {
+	info := state.Core.ControllerInfo
+	if port < uint(len(info)) && len(info[port]) > 0 {
+		return info[port]
+	}
+	descs := []libretro.ControllerDescription{}
+	for _, d := range input.Devices {
+		descs = append(descs, libretro.ControllerDescription{Desc: deviceLabels[d], ID: d})
+	}
+	return descs
+}
+
+// controllerLabel returns the name of the controller type plugged in a port
+func controllerLabel(port uint, device uint32) string {
+	for _, c := range controllers(port) {
+		if c.ID == device {
+			return c.Desc
+		}
+	}
+	if label, ok := deviceLabels[device]; ok {
+		return label
+	}
+	return fmt.Sprintf("Device %d", device)
+}
+
+// inputLabels describes what the RetroPad buttons and sticks of a port do in
+// the game, like "B = Jump"
+func inputLabels(descs []libretro.InputDescriptor, port uint) []string {
+	labels := []string{}
+	for _, d := range descs {
+		if d.Port != port || d.Description == "" {
+			continue
+		}
+		var name string
+		switch d.Device & libretro.DeviceMask {
+		case libretro.DeviceJoypad:
+			name = actionLabel(uint32(d.ID))
+		case libretro.DeviceAnalog:
+			if d.Index >= uint(len(analogLabels)) {
+				continue
+			}
+			name = analogLabels[d.Index]
+			if d.Index == uint(libretro.DeviceIndexAnalogButton) {
+				name += " " + actionLabel(uint32(d.ID))
+			} else if uint32(d.ID) == libretro.DeviceIDAnalogX {
+				name += " X"
+			} else {
+				name += " Y"
+			}
+		default:
+			continue
+		}
+		if name == "" {
+			continue
+		}
+		labels = append(labels, name+" = "+d.Description)
+	}
+	return labels
+}
+
+// setPortDevice plugs the next or previous controller type in a port, and
+// saves the choice in the remapping file of the core
+func setPortDevice(port uint, direction int) {
+	types := controllers(port)
+	i := 0
+	for k, c := range types {
+		if c.ID == input.PortDevice(port) {
+			i = k
+		}
+	}
+	i = (i + direction + len(types)) % len(types)
+	device := types[i].ID
+
+	_, path, _ := input.RemapScopes()
+	if path == "" {
+		return
+	}
+	if err := input.SavePortDevice(path, int(port), device); err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Menu", "Could not save controller: %s", err)
+		return
+	}
+	state.Core.SetControllerPortDevice(port, device)
+}
+
+func (s *sceneControls) Entry() *entry {
+	return &s.entry
+}
+
+func (s *sceneControls) segueMount() {
+	genericSegueMount(&s.entry)
+}
+
+func (s *sceneControls) segueNext() {
+	genericSegueNext(&s.entry)
+}
+
+func (s *sceneControls) segueBack() {
+	genericAnimate(&s.entry)
+}
+
+func (s *sceneControls) update(dt float32) {
+	genericInput(&s.entry, dt)
+}
+
+func (s *sceneControls) render() {
+	genericRender(&s.entry)
+}
+
+func (s *sceneControls) drawHintBar() {
+	genericDrawHintBar()
+}
@@ -9,6 +9,7 @@ import (
 type sceneDialog struct {
 	entry
 	title, line1, line2 string
+	callbackCancel      func() // optional callback executed when the user cancels
 }
 
 func buildYesNoDialog(title, line1, line2 string, callbackOK func()) Scene {
@@ -49,6 +50,9 @@ func (s *sceneDialog) update(dt float32) {
 		audio.PlayEffect(audio.Effects["cancel"])
 		menu.stack[len(menu.stack)-2].segueBack()
 		menu.stack = menu.stack[:len(menu.stack)-1]
+		if s.callbackCancel != nil {
+			s.callbackCancel()
+		}
 	}
 }
 
@@ -0,0 +1,213 @@
+package menu
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/utils"
+	"github.com/libretro/ludo/video"
+)
+
+type sceneDisplay struct {
+	entry
+}
+
+// customAspectIncr steps a custom aspect ratio by 0.05, between 0.5 and 3
+func customAspectIncr(v float32, direction int) float32 {
+	v = float32(math.Round(float64(v*100)+float64(5*direction))) / 100
+	if v < 0.5 {
+		v = 0.5
+	}
+	if v > 3 {
+		v = 3
+	}
+	return v
+}
+
+// buildDisplay lists the display options of the running core. They edit the
+// global settings unless the core has its own options.
+func buildDisplay() Scene {
+	var list sceneDisplay
+	list.label = "Display"
+
+	name := menu.CoreName
+
+	// setDisplay stores the options in the core overrides if any, else in the
+	// global settings
+	setDisplay := func(d settings.Display) {
+		if _, ok := settings.Current.CoreDisplay[name]; ok {
+			settings.Current.CoreDisplay[name] = d
+		} else {
+			settings.Current.VideoAspect = d.Aspect
+			settings.Current.VideoCustomAspect = d.CustomAspect
+			settings.Current.VideoIntegerScale = d.IntegerScale
+			settings.Current.VideoCropOverscan = d.CropOverscan
+		}
+		settings.Save()
+	}
+
+	list.children = append(list.children, entry{
+		label: "Per-Core Display",
+		icon:  "subsetting",
+		value: func() interface{} {
+			_, ok := settings.Current.CoreDisplay[name]
+			return ok
+		},
+		widget: widgets["switch"],
+		incr: func(direction int) {
+			if _, ok := settings.Current.CoreDisplay[name]; ok {
+				delete(settings.Current.CoreDisplay, name)
+			} else {
+				if settings.Current.CoreDisplay == nil {
+					settings.Current.CoreDisplay = map[string]settings.Display{}
+				}
+				settings.Current.CoreDisplay[name] = settings.DisplayFor(name)
+			}
+			settings.Save()
+		},
+	})
+
+	list.children = append(list.children, entry{
+		label: "Aspect Ratio",
+		icon:  "subsetting",
+		stringValue: func() string {
+			return fmt.Sprintf("<%s>", settings.DisplayFor(name).Aspect)
+		},
+		incr: func(direction int) {
+			d := settings.DisplayFor(name)
+			i := utils.IndexOfString(d.Aspect, video.AspectModes)
+			i += direction
+			if i < 0 {
+				i = len(video.AspectModes) - 1
+			}
+			if i > len(video.AspectModes)-1 {
+				i = 0
+			}
+			d.Aspect = video.AspectModes[i]
+			setDisplay(d)
+		},
+	})
+
+	list.children = append(list.children, entry{
+		label: "Custom Aspect Ratio",
+		icon:  "subsetting",
+		stringValue: func() string {
+			return fmt.Sprintf("%.2f", settings.DisplayFor(name).CustomAspect)
+		},
+		incr: func(direction int) {
+			d := settings.DisplayFor(name)
+			d.CustomAspect = customAspectIncr(d.CustomAspect, direction)
+			setDisplay(d)
+		},
+	})
+
+	list.children = append(list.children, entry{
+		label: "Integer Scaling",
+		icon:  "subsetting",
+		value: func() interface{} {
+			return settings.DisplayFor(name).IntegerScale
+		},
+		widget: widgets["switch"],
+		incr: func(direction int) {
+			d := settings.DisplayFor(name)
+			d.IntegerScale = !d.IntegerScale
+			setDisplay(d)
+		},
+	})
+
+	list.children = append(list.children, entry{
+		label: "Crop Overscan",
+		icon:  "subsetting",
+		value: func() interface{} {
+			return settings.DisplayFor(name).CropOverscan
+		},
+		widget: widgets["switch"],
+		incr: func(direction int) {
+			d := settings.DisplayFor(name)
+			d.CropOverscan = !d.CropOverscan
+			setDisplay(d)
+		},
+	})
+
+	sides := []struct {
+		label string
+		value func(*settings.Crop) *int
+	}{
+		{"Crop Left", func(c *settings.Crop) *int { return &c.Left }},
+		{"Crop Right", func(c *settings.Crop) *int { return &c.Right }},
+		{"Crop Top", func(c *settings.Crop) *int { return &c.Top }},
+		{"Crop Bottom", func(c *settings.Crop) *int { return &c.Bottom }},
+	}
+	for _, side := range sides {
+		side := side
+		list.children = append(list.children, entry{
+			label: side.label,
+			icon:  "subsetting",
+			stringValue: func() string {
+				c := settings.Current.OverscanCrop[name]
+				return fmt.Sprintf("%dpx", *side.value(&c))
+			},
+			incr: func(direction int) {
+				c := settings.Current.OverscanCrop[name]
+				v := side.value(&c)
+				*v += direction
+				if *v < 0 {
+					*v = 0
+				}
+				if *v > 64 {
+					*v = 64
+				}
+				if settings.Current.OverscanCrop == nil {
+					settings.Current.OverscanCrop = map[string]settings.Crop{}
+				}
+				settings.Current.OverscanCrop[name] = c
+				settings.Save()
+			},
+		})
+	}
+
+	list.segueMount()
+
+	return &list
+}
+
+func (s *sceneDisplay) Entry() *entry {
+	return &s.entry
+}
+
+func (s *sceneDisplay) segueMount() {
+	genericSegueMount(&s.entry)
+}
+
+func (s *sceneDisplay) segueNext() {
+	genericSegueNext(&s.entry)
+}
+
+func (s *sceneDisplay) segueBack() {
+	genericAnimate(&s.entry)
+}
+
+func (s *sceneDisplay) update(dt float32) {
+	genericInput(&s.entry, dt)
+}
+
+func (s *sceneDisplay) render() {
+	genericRender(&s.entry)
+}
+
+func (s *sceneDisplay) drawHintBar() {
+	w, h := menu.GetFramebufferSize()
+	menu.DrawRect(0, float32(h)-70*menu.ratio, float32(w), 70*menu.ratio, 0, lightGrey)
+
+	_, upDown, leftRight, _, b, _, _, _, _, guide := hintIcons()
+
+	var stack float32
+	if state.CoreRunning {
+		stackHint(&stack, guide, "RESUME", h)
+	}
+	stackHint(&stack, upDown, "NAVIGATE", h)
+	stackHint(&stack, b, "BACK", h)
+	stackHint(&stack, leftRight, "SET", h)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/libretro/ludo/core"
 	"github.com/libretro/ludo/history"
 	ntf "github.com/libretro/ludo/notifications"
+	"github.com/libretro/ludo/savestates"
 	"github.com/libretro/ludo/state"
 )
 
@@ -26,7 +27,7 @@ func buildHistory() Scene {
 			label:      strippedName,
 			subLabel:   game.System,
 			gameName:   game.Name,
-			path:       game.Path,
+			path:       game.Key(),
 			system:     game.System,
 			tags:       tags,
 			callbackOK: func() { loadHistoryEntry(&list, game) },
@@ -46,7 +47,7 @@ func buildHistory() Scene {
 }
 
 func loadHistoryEntry(list Scene, game history.Game) {
-	if _, err := os.Stat(game.Path); os.IsNotExist(err) {
+	if _, err := os.Stat(core.GameFile(game.Path)); game.Path != "" && os.IsNotExist(err) {
 		ntf.DisplayAndLog(ntf.Error, "Menu", "Game not found.")
 		return
 	}
@@ -62,8 +63,13 @@ func loadHistoryEntry(list Scene, game history.Game) {
 			return
 		}
 	}
-	if state.GamePath != game.Path {
-		err := core.LoadGame(game.Path)
+	if state.GamePath != game.Path || state.ContentName != "" || !state.CoreRunning {
+		var err error
+		if game.Path == "" {
+			err = core.LoadNoGame()
+		} else {
+			err = core.LoadGame(game.Path)
+		}
 		if err != nil {
 			ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
 			return
@@ -77,17 +83,39 @@ func loadHistoryEntry(list Scene, game history.Game) {
 		list.segueNext()
 		menu.Push(buildQuickMenu())
 		menu.tweens.FastForward() // position the elements without animating
-		state.MenuActive = false
+		if _, err := os.Stat(game.Savestate); game.Savestate != "" && err == nil {
+			askResumeConfirmation(game.Savestate)
+		} else {
+			state.MenuActive = false
+		}
 	} else {
 		list.segueNext()
 		menu.Push(buildQuickMenu())
 	}
 }
 
+// askResumeConfirmation offers to resume the game from the state saved when
+// it was last unloaded
+func askResumeConfirmation(path string) {
+	dialog := buildYesNoDialog(
+		"Resume",
+		"A state was saved when you last quit this game.",
+		"Do you want to continue where you left off?", func() {
+			if err := savestates.Load(path); err != nil {
+				ntf.DisplayAndLog(ntf.Error, "Menu", "Could not resume: %s", err)
+			}
+			state.MenuActive = false
+		}).(*sceneDialog)
+	dialog.callbackCancel = func() {
+		state.MenuActive = false
+	}
+	menu.Push(dialog)
+}
+
 func removeHistoryGame(s []history.Game, game history.Game) []history.Game {
 	l := []history.Game{}
 	for _, g := range s {
-		if g.Path != game.Path {
+		if g.Key() != game.Key() {
 			l = append(l, g)
 		}
 	}
@@ -97,7 +125,7 @@ func removeHistoryGame(s []history.Game, game history.Game) []history.Game {
 func removeHistoryEntry(s []entry, game history.Game) []entry {
 	l := []entry{}
 	for _, g := range s {
-		if g.path != game.Path {
+		if g.path != game.Key() {
 			l = append(l, g)
 		}
 	}
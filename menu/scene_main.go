@@ -110,6 +110,31 @@ func buildMainMenu() Scene {
 		},
 	})
 
+	if state.Core != nil && state.Core.SupportNoGame {
+		list.children = append(list.children, entry{
+			label: "Start Core",
+			icon:  "subsetting",
+			callbackOK: func() {
+				startCore()
+			},
+		})
+	}
+
+	list.children = append(list.children, entry{
+		label: "Load Subsystem",
+		icon:  "subsetting",
+		callbackOK: func() {
+			if state.Core == nil {
+				ntf.DisplayAndLog(ntf.Warning, "Menu", "Please load a core first.")
+			} else if len(state.Core.SubsystemInfo) == 0 {
+				ntf.DisplayAndLog(ntf.Warning, "Menu", "This core has no subsystems.")
+			} else {
+				list.segueNext()
+				menu.Push(buildSubsystems())
+			}
+		},
+	})
+
 	if state.LudOS {
 		list.children = append(list.children, entry{
 			label: "Updater",
@@ -159,10 +184,32 @@ func coreExplorerCb(path string) {
 		return
 	}
 	ntf.DisplayAndLog(ntf.Success, "Core", "Core loaded: %s", filepath.Base(path))
+
+	// Rebuild the main menu below the explorer, its entries depend on the
+	// features of the core
+	if len(menu.stack) > 1 {
+		if _, ok := menu.stack[1].(*sceneMain); ok {
+			main := buildMainMenu()
+			main.segueNext()
+			menu.stack[1] = main
+		}
+	}
 }
 
 // triggered when a game is selected in the file explorer of Load Game
 func gameExplorerCb(path string) {
+	// Let the user pick the game when an archive contains several
+	roms, err := core.ArchiveRoms(path)
+	if err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Core", err.Error())
+		return
+	}
+	if len(roms) > 1 {
+		menu.stack[len(menu.stack)-1].segueNext()
+		menu.Push(buildArchive(path, roms))
+		return
+	}
+
 	if err := core.LoadGame(path); err != nil {
 		ntf.DisplayAndLog(ntf.Error, "Core", err.Error())
 		return
@@ -176,6 +223,20 @@ func gameExplorerCb(path string) {
 	state.MenuActive = false
 }
 
+// startCore runs a core that doesn't need a game
+func startCore() {
+	if err := core.LoadNoGame(); err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Core", err.Error())
+		return
+	}
+	history.Push(history.Game{
+		Name:     prettifyCoreName(utils.FileName(state.CorePath)),
+		CorePath: state.CorePath,
+	})
+	menu.WarpToQuickMenu()
+	state.MenuActive = false
+}
+
 // Shutdown the operating system
 func cleanShutdown() {
 	core.UnloadGame()
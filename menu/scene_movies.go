@@ -0,0 +1,126 @@
+package menu
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/libretro/ludo/movie"
+	ntf "github.com/libretro/ludo/notifications"
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/utils"
+)
+
+type sceneMovies struct {
+	entry
+}
+
+func buildMovies() Scene {
+	var list sceneMovies
+	list.label = "Movies"
+
+	if movie.Active() {
+		list.children = append(list.children, entry{
+			label: "Stop Movie",
+			icon:  "subsetting",
+			stringValue: func() string {
+				if movie.Recording() {
+					return "Recording"
+				}
+				return "Playing"
+			},
+			callbackOK: func() {
+				err := movie.Stop()
+				if err != nil {
+					ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
+					return
+				}
+				menu.stack[len(menu.stack)-1] = buildMovies()
+				menu.tweens.FastForward()
+				ntf.DisplayAndLog(ntf.Success, "Menu", "Movie stopped.")
+			},
+		})
+
+		list.segueMount()
+		return &list
+	}
+
+	record := func(fromState bool) {
+		path, err := movie.Record(fromState)
+		if err != nil {
+			ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
+			return
+		}
+		state.MenuActive = false
+		state.FastForward = false
+		ntf.DisplayAndLog(ntf.Success, "Menu", "Recording to %s.", utils.FileName(path))
+	}
+
+	list.children = append(list.children, entry{
+		label:      "Record From Power-On",
+		icon:       "reset",
+		callbackOK: func() { record(false) },
+	})
+
+	list.children = append(list.children, entry{
+		label:      "Record From Current State",
+		icon:       "savestate",
+		callbackOK: func() { record(true) },
+	})
+
+	gameName := utils.FileName(state.ContentPath())
+	paths, _ := filepath.Glob(settings.Current.MoviesDirectory + "/" + gameName + "@*.movie")
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	for _, path := range paths {
+		path := path
+		date := strings.Replace(utils.FileName(path), gameName+"@", "", 1)
+		list.children = append(list.children, entry{
+			label: "Play " + date,
+			icon:  "loadstate",
+			path:  path,
+			callbackOK: func() {
+				err := movie.Play(path)
+				if err != nil {
+					ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
+					return
+				}
+				state.MenuActive = false
+				state.FastForward = false
+				ntf.DisplayAndLog(ntf.Success, "Menu", "Playing movie.")
+			},
+		})
+	}
+
+	list.segueMount()
+
+	return &list
+}
+
+func (s *sceneMovies) Entry() *entry {
+	return &s.entry
+}
+
+func (s *sceneMovies) segueMount() {
+	genericSegueMount(&s.entry)
+}
+
+func (s *sceneMovies) segueNext() {
+	genericSegueNext(&s.entry)
+}
+
+func (s *sceneMovies) segueBack() {
+	genericAnimate(&s.entry)
+}
+
+func (s *sceneMovies) update(dt float32) {
+	genericInput(&s.entry, dt)
+}
+
+func (s *sceneMovies) render() {
+	genericRender(&s.entry)
+}
+
+func (s *sceneMovies) drawHintBar() {
+	genericDrawHintBar()
+}
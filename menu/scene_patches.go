@@ -0,0 +1,95 @@
+package menu
+
+import (
+	"path/filepath"
+
+	ntf "github.com/libretro/ludo/notifications"
+	"github.com/libretro/ludo/patch"
+	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/utils"
+)
+
+type scenePatches struct {
+	entry
+}
+
+func buildPatches() Scene {
+	var list scenePatches
+	list.label = "Patches"
+
+	applied := patch.Applied()
+	for _, path := range patch.Find(state.GamePath) {
+		path := path
+		label := filepath.Base(path)
+		if utils.IndexOfString(path, applied) >= 0 {
+			label += " (applied)"
+		}
+		list.children = append(list.children, entry{
+			label: label,
+			icon:  "subsetting",
+			path:  path,
+			value: func() interface{} {
+				return patch.Enabled(path)
+			},
+			widget: widgets["switch"],
+			callbackOK: func() {
+				err := patch.SetEnabled(path, !patch.Enabled(path))
+				if err != nil {
+					ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
+					return
+				}
+				ntf.DisplayAndLog(ntf.Info, "Menu", "Reload the game to apply the change.")
+			},
+		})
+	}
+
+	if len(list.children) == 0 {
+		list.children = append(list.children, entry{
+			label: "No patch",
+			icon:  "subsetting",
+		})
+	}
+
+	list.segueMount()
+
+	return &list
+}
+
+func (s *scenePatches) Entry() *entry {
+	return &s.entry
+}
+
+func (s *scenePatches) segueMount() {
+	genericSegueMount(&s.entry)
+}
+
+func (s *scenePatches) segueNext() {
+	genericSegueNext(&s.entry)
+}
+
+func (s *scenePatches) segueBack() {
+	genericAnimate(&s.entry)
+}
+
+func (s *scenePatches) update(dt float32) {
+	genericInput(&s.entry, dt)
+}
+
+func (s *scenePatches) render() {
+	genericRender(&s.entry)
+}
+
+func (s *scenePatches) drawHintBar() {
+	w, h := menu.GetFramebufferSize()
+	menu.DrawRect(0, float32(h)-70*menu.ratio, float32(w), 70*menu.ratio, 0, lightGrey)
+
+	_, upDown, _, a, b, _, _, _, _, guide := hintIcons()
+
+	var stack float32
+	if state.CoreRunning {
+		stackHint(&stack, guide, "RESUME", h)
+	}
+	stackHint(&stack, upDown, "NAVIGATE", h)
+	stackHint(&stack, b, "BACK", h)
+	stackHint(&stack, a, "TOGGLE", h)
+}
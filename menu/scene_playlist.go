@@ -84,7 +84,7 @@ func extractTags(name string) (string, []string) {
 }
 
 func loadPlaylistEntry(list *scenePlaylist, playlist string, game playlists.Game) {
-	if _, err := os.Stat(game.Path); os.IsNotExist(err) {
+	if _, err := os.Stat(core.GameFile(game.Path)); os.IsNotExist(err) {
 		ntf.DisplayAndLog(ntf.Error, "Menu", "Game not found.")
 		return
 	}
@@ -1,7 +1,13 @@
 package menu
 
 import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/libretro/ludo/cheats"
 	ntf "github.com/libretro/ludo/notifications"
+	"github.com/libretro/ludo/patch"
+	"github.com/libretro/ludo/recording"
 	"github.com/libretro/ludo/state"
 	"github.com/libretro/ludo/utils"
 )
@@ -42,11 +48,20 @@ func buildQuickMenu() Scene {
 		},
 	})
 
+	list.children = append(list.children, entry{
+		label: "Movies",
+		icon:  "subsetting",
+		callbackOK: func() {
+			list.segueNext()
+			menu.Push(buildMovies())
+		},
+	})
+
 	list.children = append(list.children, entry{
 		label: "Take Screenshot",
 		icon:  "screenshot",
 		callbackOK: func() {
-			name := utils.DatedName(state.GamePath)
+			name := utils.DatedName(state.ContentPath())
 			err := menu.TakeScreenshot(name)
 			if err != nil {
 				ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
@@ -56,6 +71,27 @@ func buildQuickMenu() Scene {
 		},
 	})
 
+	list.children = append(list.children, entry{
+		label: "Record Video",
+		icon:  "screenshot",
+		value: func() interface{} {
+			return recording.Active()
+		},
+		widget: widgets["switch"],
+		callbackOK: func() {
+			toggleRecording()
+		},
+	})
+
+	list.children = append(list.children, entry{
+		label: "Controls",
+		icon:  "subsetting",
+		callbackOK: func() {
+			list.segueNext()
+			menu.Push(buildControls())
+		},
+	})
+
 	list.children = append(list.children, entry{
 		label: "Options",
 		icon:  "subsetting",
@@ -65,6 +101,40 @@ func buildQuickMenu() Scene {
 		},
 	})
 
+	list.children = append(list.children, entry{
+		label: "Display",
+		icon:  "subsetting",
+		callbackOK: func() {
+			list.segueNext()
+			menu.Push(buildDisplay())
+		},
+	})
+
+	if len(cheats.List()) > 0 {
+		list.children = append(list.children, entry{
+			label: "Cheats",
+			icon:  "subsetting",
+			callbackOK: func() {
+				list.segueNext()
+				menu.Push(buildCheats())
+			},
+		})
+	}
+
+	if state.GamePath != "" && len(patch.Find(state.GamePath)) > 0 {
+		list.children = append(list.children, entry{
+			label: "Patches",
+			icon:  "subsetting",
+			stringValue: func() string {
+				return fmt.Sprintf("%d applied", len(patch.Applied()))
+			},
+			callbackOK: func() {
+				list.segueNext()
+				menu.Push(buildPatches())
+			},
+		})
+	}
+
 	if state.Core != nil && state.Core.DiskControlCallback != nil {
 		list.children = append(list.children, entry{
 			label: "Disk Control",
@@ -81,6 +151,24 @@ func buildQuickMenu() Scene {
 	return &list
 }
 
+// toggleRecording starts or stops recording a clip of the running game
+func toggleRecording() {
+	if recording.Active() {
+		if err := recording.Stop(); err != nil {
+			ntf.DisplayAndLog(ntf.Error, "Recording", err.Error())
+		} else {
+			ntf.DisplayAndLog(ntf.Success, "Recording", "Recording saved.")
+		}
+		return
+	}
+	path, err := recording.Start(state.Core.GetSystemAVInfo().Timing)
+	if err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Recording", err.Error())
+		return
+	}
+	ntf.DisplayAndLog(ntf.Info, "Recording", "Recording to %s.", filepath.Base(path))
+}
+
 func (s *sceneQuick) Entry() *entry {
 	return &s.entry
 }
@@ -0,0 +1,184 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/libretro/ludo/input"
+	ntf "github.com/libretro/ludo/notifications"
+)
+
+// captureTimeout is the time in seconds to press a key or a button before a
+// rebinding is cancelled
+const captureTimeout = 5
+
+var remapScopes = []string{"Global", "Core", "Game"}
+
+type sceneRemap struct {
+	entry
+	player    int
+	scope     int
+	capturing bool
+	released  bool    // all inputs were released since the capture started
+	remaining float32 // time left to capture
+	action    uint32  // action being rebound
+}
+
+func buildRemap() Scene {
+	var list sceneRemap
+	list.label = "Input Remapping"
+
+	list.children = append(list.children, entry{
+		label:       "Player",
+		icon:        "subsetting",
+		stringValue: func() string { return fmt.Sprintf("%d", list.player+1) },
+		incr: func(direction int) {
+			list.player = (list.player + direction + input.MaxPlayers) % input.MaxPlayers
+		},
+	})
+
+	list.children = append(list.children, entry{
+		label: "Save To",
+		icon:  "subsetting",
+		stringValue: func() string {
+			list.scopePath()
+			return remapScopes[list.scope]
+		},
+		incr: func(direction int) {
+			n := len(list.scopePaths())
+			list.scope = (list.scope + direction + n) % n
+		},
+	})
+
+	for _, action := range input.Actions {
+		action := action
+		list.children = append(list.children, entry{
+			label: actionLabel(action),
+			icon:  "subsetting",
+			stringValue: func() string {
+				if list.capturing && list.action == action {
+					return fmt.Sprintf("Press a key or button (%d)", int(list.remaining)+1)
+				}
+				key, button := input.BindNames(list.player, action)
+				if key == "" {
+					key = "-"
+				}
+				if button == "" {
+					button = "-"
+				}
+				return key + " / " + button
+			},
+			callbackOK: func() {
+				list.capturing = true
+				list.released = false
+				list.remaining = captureTimeout
+				list.action = action
+			},
+		})
+	}
+
+	list.children = append(list.children, entry{
+		label: "Reset To Defaults",
+		icon:  "reset",
+		callbackOK: func() {
+			path := list.scopePath()
+			if err := input.ResetRemap(path); err != nil {
+				ntf.DisplayAndLog(ntf.Error, "Menu", "Could not reset remapping: %s", err)
+				return
+			}
+			ntf.DisplayAndLog(ntf.Success, "Menu", "%s remapping reset.", remapScopes[list.scope])
+		},
+	})
+
+	list.segueMount()
+
+	return &list
+}
+
+// actionLabel returns a readable name of an action, like "Fast Forward Toggle"
+func actionLabel(action uint32) string {
+	return strings.Title(strings.Replace(input.ActionName(action), "_", " ", -1))
+}
+
+// scopePaths returns the remapping files that bindings can be saved to. Core
+// and game files are only available while a game is running.
+func (s *sceneRemap) scopePaths() []string {
+	global, core, game := input.RemapScopes()
+	paths := []string{global}
+	if core != "" {
+		paths = append(paths, core)
+	}
+	if game != "" {
+		paths = append(paths, game)
+	}
+	return paths
+}
+
+// scopePath returns the remapping file selected in the Save To entry
+func (s *sceneRemap) scopePath() string {
+	paths := s.scopePaths()
+	if s.scope >= len(paths) {
+		s.scope = 0
+	}
+	return paths[s.scope]
+}
+
+// capture waits for all inputs to be released, then binds the first key or
+// button pressed to the action being rebound
+func (s *sceneRemap) capture(dt float32) {
+	s.remaining -= dt
+	if s.remaining <= 0 {
+		s.capturing = false
+		ntf.DisplayAndLog(ntf.Warning, "Menu", "Rebinding cancelled.")
+		return
+	}
+
+	device, name, ok := input.Capture(s.player)
+	if !s.released {
+		s.released = !ok
+		return
+	}
+	if !ok {
+		return
+	}
+
+	s.capturing = false
+	path := s.scopePath()
+	if err := input.SaveBind(path, s.player, s.action, device, name); err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Menu", "Could not save remapping: %s", err)
+		return
+	}
+	ntf.DisplayAndLog(ntf.Success, "Menu", "%s bound to %s.", actionLabel(s.action), name)
+}
+
+func (s *sceneRemap) Entry() *entry {
+	return &s.entry
+}
+
+func (s *sceneRemap) segueMount() {
+	genericSegueMount(&s.entry)
+}
+
+func (s *sceneRemap) segueNext() {
+	genericSegueNext(&s.entry)
+}
+
+func (s *sceneRemap) segueBack() {
+	genericAnimate(&s.entry)
+}
+
+func (s *sceneRemap) update(dt float32) {
+	if s.capturing {
+		s.capture(dt)
+		return
+	}
+	genericInput(&s.entry, dt)
+}
+
+func (s *sceneRemap) render() {
+	genericRender(&s.entry)
+}
+
+func (s *sceneRemap) drawHintBar() {
+	genericDrawHintBar()
+}
@@ -1,10 +1,11 @@
 package menu
 
 import (
-	"os"
+	"fmt"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	ntf "github.com/libretro/ludo/notifications"
 	"github.com/libretro/ludo/savestates"
@@ -25,36 +26,41 @@ func buildSavestates() Scene {
 		label: "Save State",
 		icon:  "savestate",
 		callbackOK: func() {
-			name := utils.DatedName(state.GamePath)
-			err := menu.TakeScreenshot(name)
-			if err != nil {
-				ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
-			}
-			err = savestates.Save(name)
-			if err != nil {
-				ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
-			} else {
+			if saveState("") {
 				menu.stack[len(menu.stack)-1] = buildSavestates()
 				menu.tweens.FastForward()
-				ntf.DisplayAndLog(ntf.Success, "Menu", "State saved.")
 			}
 		},
 	})
 
-	gameName := utils.FileName(state.GamePath)
+	list.children = append(list.children, entry{
+		label: "Save State With Label",
+		icon:  "savestate",
+		callbackOK: func() {
+			list.segueNext()
+			menu.Push(buildKeyboard("Savestate Label", func(label string) {
+				if saveState(label) {
+					menu.stack[len(menu.stack)-2] = buildSavestates()
+				}
+			}))
+		},
+	})
+
+	gameName := utils.FileName(state.ContentPath())
 	paths, _ := filepath.Glob(settings.Current.SavestatesDirectory + "/" + gameName + "@*.state")
 	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
 	for _, path := range paths {
 		path := path
-		date := strings.Replace(utils.FileName(path), gameName+"@", "", 1)
+		label, subLabel := describeSavestate(path, gameName)
 		list.children = append(list.children, entry{
-			label: "Load " + date,
-			icon:  "loadstate",
-			path:  path,
+			label:    "Load " + label,
+			subLabel: subLabel,
+			icon:     "loadstate",
+			path:     path,
 			callbackOK: func() {
 				err := savestates.Load(path)
 				if err != nil {
-					ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
+					ntf.DisplayAndLog(ntf.Error, "Menu", "Could not load state: %s", err)
 				} else {
 					state.MenuActive = false
 
@@ -70,6 +76,44 @@ func buildSavestates() Scene {
 	return &list
 }
 
+// saveState takes a screenshot and saves the state with a dated name
+func saveState(label string) bool {
+	name := utils.DatedName(state.ContentPath())
+	err := menu.TakeScreenshot(name)
+	if err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
+	}
+	err = savestates.SaveWithLabel(name, label)
+	if err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Menu", err.Error())
+		return false
+	}
+	ntf.DisplayAndLog(ntf.Success, "Menu", "State saved.")
+	return true
+}
+
+// describeSavestate returns the label of a savestate entry, and a sub label
+// made from the metadata of the state
+func describeSavestate(path, gameName string) (string, string) {
+	label := strings.Replace(utils.FileName(path), gameName+"@", "", 1)
+	if strings.HasPrefix(label, "slot") {
+		label = "Slot " + strings.TrimPrefix(label, "slot")
+	}
+	if label == "auto" {
+		label = "Auto Save"
+	}
+
+	m, err := savestates.ReadMeta(path)
+	if err != nil || m == nil {
+		return label, ""
+	}
+	if m.Label != "" {
+		label = m.Label
+	}
+	playtime := time.Duration(m.Playtime) * time.Second
+	return label, fmt.Sprintf("%s %s, played %s", m.CoreName, m.CoreVersion, playtime)
+}
+
 func (s *sceneSavestates) Entry() *entry {
 	return &s.entry
 }
@@ -102,7 +146,7 @@ func removeSavestateEntry(s []entry, path string) []entry {
 }
 
 func deleteSavestateEntry(list *sceneSavestates, path string) {
-	err := os.Remove(path)
+	err := savestates.Delete(path)
 	if err != nil {
 		ntf.DisplayAndLog(ntf.Error, "Menu", "Could not delete savestate: %s", err.Error())
 		return
@@ -143,7 +187,7 @@ func (s *sceneSavestates) render() {
 				float32(h)*e.yp-14*menu.ratio-64*e.scale*menu.ratio+fontOffset,
 				170*menu.ratio*e.scale, 128*menu.ratio*e.scale, 0.02/e.scale,
 				textColor.Alpha(e.iconAlpha))
-			if i == 0 {
+			if i < 2 {
 				menu.DrawImage(menu.icons["savestate"],
 					680*menu.ratio-25*e.scale*menu.ratio,
 					float32(h)*e.yp-14*menu.ratio-25*e.scale*menu.ratio+fontOffset,
@@ -156,6 +200,14 @@ func (s *sceneSavestates) render() {
 				840*menu.ratio,
 				float32(h)*e.yp+fontOffset,
 				0.5*menu.ratio, e.label)
+
+			if e.subLabel != "" {
+				menu.Font.SetColor(textColor.Alpha(e.labelAlpha * 0.6))
+				menu.Font.Printf(
+					840*menu.ratio,
+					float32(h)*e.yp+fontOffset+40*menu.ratio,
+					0.4*menu.ratio, e.subLabel)
+			}
 		}
 	}
 }
@@ -174,7 +226,7 @@ func (s *sceneSavestates) drawHintBar() {
 	}
 	stackHint(&stack, upDown, "NAVIGATE", h)
 	stackHint(&stack, b, "BACK", h)
-	if ptr == 0 {
+	if ptr < 2 {
 		stackHint(&stack, a, "SAVE", h)
 	} else {
 		stackHint(&stack, a, "LOAD", h)
@@ -9,11 +9,14 @@ import (
 	"github.com/go-gl/glfw/v3.3/glfw"
 
 	"github.com/libretro/ludo/audio"
+	"github.com/libretro/ludo/input"
 	"github.com/libretro/ludo/ludos"
 	ntf "github.com/libretro/ludo/notifications"
+	"github.com/libretro/ludo/scheduler"
 	"github.com/libretro/ludo/settings"
 	"github.com/libretro/ludo/state"
 	"github.com/libretro/ludo/utils"
+	"github.com/libretro/ludo/video"
 )
 
 type sceneSettings struct {
@@ -33,6 +36,15 @@ func buildSettings() Scene {
 	var list sceneSettings
 	list.label = "Settings"
 
+	list.children = append(list.children, entry{
+		label: "Input Remapping",
+		icon:  "subsetting",
+		callbackOK: func() {
+			list.segueNext()
+			menu.Push(buildRemap())
+		},
+	})
+
 	if state.LudOS {
 		list.children = append(list.children, entry{
 			label:       "Wi-Fi",
@@ -184,6 +196,7 @@ var incrCallbacks = map[string]callbackIncrement{
 	},
 	"VideoFilter": func(f *structs.Field, direction int) {
 		filters := []string{"Raw", "Smooth", "Pixel Perfect", "CRT", "LCD"}
+		filters = append(filters, video.Presets()...)
 		v := f.Value().(string)
 		i := utils.IndexOfString(v, filters)
 		i += direction
@@ -197,6 +210,73 @@ var incrCallbacks = map[string]callbackIncrement{
 		menu.UpdateFilter(filters[i])
 		settings.Save()
 	},
+	"VideoAspect": func(f *structs.Field, direction int) {
+		v := f.Value().(string)
+		i := utils.IndexOfString(v, video.AspectModes)
+		i += direction
+		if i < 0 {
+			i = len(video.AspectModes) - 1
+		}
+		if i > len(video.AspectModes)-1 {
+			i = 0
+		}
+		f.Set(video.AspectModes[i])
+		settings.Save()
+	},
+	"VideoCustomAspect": func(f *structs.Field, direction int) {
+		f.Set(customAspectIncr(f.Value().(float32), direction))
+		settings.Save()
+	},
+	"VideoIntegerScale": func(f *structs.Field, direction int) {
+		v := f.Value().(bool)
+		v = !v
+		f.Set(v)
+		settings.Save()
+	},
+	"VideoCropOverscan": func(f *structs.Field, direction int) {
+		v := f.Value().(bool)
+		v = !v
+		f.Set(v)
+		settings.Save()
+	},
+	"VideoSyncMode": func(f *structs.Field, direction int) {
+		v := f.Value().(string)
+		i := utils.IndexOfString(v, scheduler.Modes)
+		i += direction
+		if i < 0 {
+			i = len(scheduler.Modes) - 1
+		}
+		if i > len(scheduler.Modes)-1 {
+			i = 0
+		}
+		f.Set(scheduler.Modes[i])
+		scheduler.Reset()
+		settings.Save()
+	},
+	"VideoFrameSkip": func(f *structs.Field, direction int) {
+		v := f.Value().(int)
+		v += direction
+		if v < 0 {
+			v = 0
+		}
+		if v > 5 {
+			v = 5
+		}
+		f.Set(v)
+		settings.Save()
+	},
+	"FastForwardRatio": func(f *structs.Field, direction int) {
+		v := f.Value().(int)
+		v += direction
+		if v < 2 {
+			v = 2
+		}
+		if v > 10 {
+			v = 10
+		}
+		f.Set(v)
+		settings.Save()
+	},
 	"VideoDarkMode": func(f *structs.Field, direction int) {
 		v := f.Value().(bool)
 		v = !v
@@ -209,6 +289,42 @@ var incrCallbacks = map[string]callbackIncrement{
 		f.Set(v)
 		settings.Save()
 	},
+	"RewindEnabled": func(f *structs.Field, direction int) {
+		v := f.Value().(bool)
+		v = !v
+		f.Set(v)
+		settings.Save()
+	},
+	"RewindBufferSize": func(f *structs.Field, direction int) {
+		v := f.Value().(int)
+		v += 16 * direction
+		if v < 16 {
+			v = 16
+		}
+		if v > 1024 {
+			v = 1024
+		}
+		f.Set(v)
+		settings.Save()
+	},
+	"RewindInterval": func(f *structs.Field, direction int) {
+		v := f.Value().(int)
+		v += direction
+		if v < 1 {
+			v = 1
+		}
+		if v > 60 {
+			v = 60
+		}
+		f.Set(v)
+		settings.Save()
+	},
+	"AutoSavestate": func(f *structs.Field, direction int) {
+		v := f.Value().(bool)
+		v = !v
+		f.Set(v)
+		settings.Save()
+	},
 	"AudioVolume": func(f *structs.Field, direction int) {
 		v := f.Value().(float32)
 		v += 0.1 * float32(direction)
@@ -222,6 +338,20 @@ var incrCallbacks = map[string]callbackIncrement{
 		audio.SetVolume(v)
 		settings.Save()
 	},
+	"AudioDriver": func(f *structs.Field, direction int) {
+		v := f.Value().(string)
+		i := utils.IndexOfString(v, audio.Backends)
+		i += direction
+		if i < 0 {
+			i = len(audio.Backends) - 1
+		}
+		if i > len(audio.Backends)-1 {
+			i = 0
+		}
+		f.Set(audio.Backends[i])
+		audio.SetBackend(audio.Backends[i])
+		settings.Save()
+	},
 	"MenuAudioVolume": func(f *structs.Field, direction int) {
 		v := f.Value().(float32)
 		v += 0.1 * float32(direction)
@@ -235,6 +365,19 @@ var incrCallbacks = map[string]callbackIncrement{
 		audio.SetEffectsVolume(v)
 		settings.Save()
 	},
+	"InputRumbleStrength": func(f *structs.Field, direction int) {
+		v := f.Value().(float32)
+		v += 0.1 * float32(direction)
+		if v < 0 {
+			v = 0
+		}
+		if v > 1 {
+			v = 1
+		}
+		f.Set(v)
+		input.UpdateRumble()
+		settings.Save()
+	},
 	"ShowHiddenFiles": func(f *structs.Field, direction int) {
 		v := f.Value().(bool)
 		v = !v
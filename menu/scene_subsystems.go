@@ -0,0 +1,212 @@
+package menu
+
+import (
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/libretro/ludo/core"
+	"github.com/libretro/ludo/libretro"
+	ntf "github.com/libretro/ludo/notifications"
+	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/utils"
+)
+
+type sceneSubsystems struct {
+	entry
+}
+
+// buildSubsystems lists the special game types of the core, like Super Game
+// Boy or Sufami Turbo
+func buildSubsystems() Scene {
+	var list sceneSubsystems
+	list.label = "Load Subsystem"
+
+	for _, sub := range state.Core.SubsystemInfo {
+		sub := sub
+		list.children = append(list.children, entry{
+			label: strings.Replace(sub.Desc, "%", "%%", -1),
+			icon:  "subsetting",
+			callbackOK: func() {
+				list.segueNext()
+				menu.Push(buildSubsystem(sub))
+			},
+		})
+	}
+
+	list.segueMount()
+
+	return &list
+}
+
+func (s *sceneSubsystems) Entry() *entry {
+	return &s.entry
+}
+
+func (s *sceneSubsystems) segueMount() {
+	genericSegueMount(&s.entry)
+}
+
+func (s *sceneSubsystems) segueNext() {
+	genericSegueNext(&s.entry)
+}
+
+func (s *sceneSubsystems) segueBack() {
+	genericAnimate(&s.entry)
+}
+
+func (s *sceneSubsystems) update(dt float32) {
+	genericInput(&s.entry, dt)
+}
+
+func (s *sceneSubsystems) render() {
+	genericRender(&s.entry)
+}
+
+func (s *sceneSubsystems) drawHintBar() {
+	genericDrawHintBar()
+}
+
+type sceneSubsystem struct {
+	entry
+	sub   libretro.SubsystemInfo
+	paths []string // file picked for each rom slot
+}
+
+// buildSubsystem lets the user pick one file per rom slot of a subsystem, then
+// start the game
+func buildSubsystem(sub libretro.SubsystemInfo) Scene {
+	var list sceneSubsystem
+	list.label = sub.Desc
+	list.sub = sub
+	list.paths = make([]string, len(sub.Roms))
+
+	for i, rom := range sub.Roms {
+		i, rom := i, rom
+		list.children = append(list.children, entry{
+			label: strings.Replace(rom.Desc, "%", "%%", -1),
+			icon:  "subsetting",
+			stringValue: func() string {
+				if list.paths[i] != "" {
+					return strings.Replace(filepath.Base(list.paths[i]), "%", "%%", -1)
+				}
+				if rom.Required {
+					return "Required"
+				}
+				return "None"
+			},
+			callbackOK: func() {
+				list.pickRom(i)
+			},
+			callbackX: func() {
+				list.paths[i] = ""
+			},
+		})
+	}
+
+	list.children = append(list.children, entry{
+		label: "Start",
+		icon:  "subsetting",
+		callbackOK: func() {
+			list.start()
+		},
+	})
+
+	list.segueMount()
+
+	return &list
+}
+
+// pickRom opens a file explorer to choose the file of a rom slot. The menu
+// comes back to the subsystem once a file is picked.
+func (s *sceneSubsystem) pickRom(i int) {
+	dir := ""
+	for _, p := range s.paths {
+		if p != "" {
+			dir = filepath.Dir(p)
+		}
+	}
+	if dir == "" {
+		usr, _ := user.Current()
+		dir = usr.HomeDir
+	}
+
+	rom := s.sub.Roms[i]
+	depth := len(menu.stack)
+	s.segueNext()
+	menu.Push(buildExplorer(
+		dir,
+		romExtensions(rom),
+		func(path string) {
+			s.paths[i] = path
+			menu.stack[depth-1].segueBack()
+			menu.stack = menu.stack[:depth]
+		},
+		nil,
+		nil,
+	))
+}
+
+// start loads the subsystem game once all the required roms are picked
+func (s *sceneSubsystem) start() {
+	for i, rom := range s.sub.Roms {
+		if rom.Required && s.paths[i] == "" {
+			ntf.DisplayAndLog(ntf.Warning, "Menu", "Please select the %s.", rom.Desc)
+			return
+		}
+	}
+	if err := core.LoadGameSpecial(s.sub, s.paths); err != nil {
+		ntf.DisplayAndLog(ntf.Error, "Core", err.Error())
+		return
+	}
+	menu.WarpToQuickMenu()
+	state.MenuActive = false
+}
+
+// romExtensions converts the valid extensions of a rom slot, like "gb|gbc",
+// to the extensions filtered by the explorer. Archives are listed too when
+// the core lets the frontend extract them. Nil means all files.
+func romExtensions(rom libretro.SubsystemRom) []string {
+	if rom.ValidExtensions == "" {
+		return nil
+	}
+	exts := []string{}
+	for _, ext := range strings.Split(rom.ValidExtensions, "|") {
+		if ext == "" {
+			continue
+		}
+		exts = append(exts, "."+strings.ToLower(ext), "."+strings.ToUpper(ext))
+	}
+	if !rom.BlockExtract {
+		exts = append(exts, utils.ArchiveExts...)
+	}
+	return exts
+}
+
+func (s *sceneSubsystem) Entry() *entry {
+	return &s.entry
+}
+
+func (s *sceneSubsystem) segueMount() {
+	genericSegueMount(&s.entry)
+}
+
+func (s *sceneSubsystem) segueNext() {
+	genericSegueNext(&s.entry)
+}
+
+func (s *sceneSubsystem) segueBack() {
+	genericAnimate(&s.entry)
+}
+
+func (s *sceneSubsystem) update(dt float32) {
+	genericInput(&s.entry, dt)
+}
+
+func (s *sceneSubsystem) render() {
+	genericRender(&s.entry)
+}
+
+func (s *sceneSubsystem) drawHintBar() {
+	genericDrawHintBar()
+}
@@ -0,0 +1,196 @@
+package movie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/libretro/ludo/input"
+	lr "github.com/libretro/ludo/libretro"
+)
+
+// Version of the movie file format
+const Version = 1
+
+var magic = [8]byte{'L', 'U', 'D', 'O', 'M', 'O', 'V', 0}
+
+// Record types
+const (
+	recordInputs   byte = 1
+	recordChecksum byte = 2
+)
+
+// Header describes a movie file
+type Header struct {
+	Version  uint32
+	Interval uint32 // number of frames between two checksums
+	Core     string // library name of the core used for the recording
+	Game     string // file name of the game, without extension
+	State    []byte // savestate to start from, empty for power-on
+}
+
+// Inputs holds the game inputs of all the players for a single frame.
+// Buttons are stored as a bitmask of the joypad IDs, hotkeys are left out.
+type Inputs struct {
+	Buttons [input.MaxPlayers]uint16
+	Analog  input.AnalogStates
+}
+
+// checksum is the CRC32 of the serialized core state after a given frame
+type checksum struct {
+	Frame uint32
+	Sum   uint32
+}
+
+// inputsFromStates builds Inputs from the states of the input package
+func inputsFromStates(s input.States, a input.AnalogStates) Inputs {
+	var in Inputs
+	for p := range s {
+		for id := uint32(0); id <= lr.DeviceIDJoypadR3; id++ {
+			if s[p][id] != 0 {
+				in.Buttons[p] |= 1 << id
+			}
+		}
+	}
+	in.Analog = a
+	return in
+}
+
+// apply overwrites the game inputs of the given states, hotkeys are preserved
+func (in Inputs) apply(s *input.States, a *input.AnalogStates) {
+	for p := range s {
+		for id := uint32(0); id <= lr.DeviceIDJoypadR3; id++ {
+			s[p][id] = int16(in.Buttons[p] >> id & 1)
+		}
+	}
+	*a = in.Analog
+}
+
+type encoder struct {
+	w io.Writer
+}
+
+func (e *encoder) writeString(s string) error {
+	if err := binary.Write(e.w, binary.LittleEndian, uint32(len(s))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, s)
+	return err
+}
+
+func (e *encoder) writeHeader(h Header) error {
+	if _, err := e.w.Write(magic[:]); err != nil {
+		return err
+	}
+	if err := binary.Write(e.w, binary.LittleEndian, []uint32{h.Version, h.Interval}); err != nil {
+		return err
+	}
+	if err := e.writeString(h.Core); err != nil {
+		return err
+	}
+	if err := e.writeString(h.Game); err != nil {
+		return err
+	}
+	return e.writeString(string(h.State))
+}
+
+func (e *encoder) writeInputs(in Inputs) error {
+	if _, err := e.w.Write([]byte{recordInputs}); err != nil {
+		return err
+	}
+	return binary.Write(e.w, binary.LittleEndian, in)
+}
+
+func (e *encoder) writeChecksum(c checksum) error {
+	if _, err := e.w.Write([]byte{recordChecksum}); err != nil {
+		return err
+	}
+	return binary.Write(e.w, binary.LittleEndian, c)
+}
+
+type decoder struct {
+	r io.Reader
+}
+
+func (d *decoder) readBytes() ([]byte, error) {
+	var n uint32
+	if err := binary.Read(d.r, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	// Don't trust the length blindly, a corrupted file could make us allocate
+	// gigabytes
+	var buf bytes.Buffer
+	_, err := io.CopyN(&buf, d.r, int64(n))
+	return buf.Bytes(), unexpected(err)
+}
+
+func (d *decoder) readHeader() (Header, error) {
+	var h Header
+
+	var m [8]byte
+	if _, err := io.ReadFull(d.r, m[:]); err != nil {
+		return h, err
+	}
+	if m != magic {
+		return h, errors.New("not a movie file")
+	}
+
+	var fields [2]uint32
+	if err := binary.Read(d.r, binary.LittleEndian, &fields); err != nil {
+		return h, err
+	}
+	h.Version, h.Interval = fields[0], fields[1]
+	if h.Version != Version {
+		return h, fmt.Errorf("unsupported movie version %d", h.Version)
+	}
+
+	core, err := d.readBytes()
+	if err != nil {
+		return h, err
+	}
+	game, err := d.readBytes()
+	if err != nil {
+		return h, err
+	}
+	h.State, err = d.readBytes()
+	if err != nil {
+		return h, err
+	}
+	h.Core = string(core)
+	h.Game = string(game)
+
+	return h, nil
+}
+
+// next reads the next record. It returns an Inputs or a checksum, and io.EOF
+// at the end of the movie.
+func (d *decoder) next() (interface{}, error) {
+	var kind [1]byte
+	if _, err := io.ReadFull(d.r, kind[:]); err != nil {
+		return nil, err
+	}
+
+	switch kind[0] {
+	case recordInputs:
+		var in Inputs
+		err := binary.Read(d.r, binary.LittleEndian, &in)
+		return in, unexpected(err)
+	case recordChecksum:
+		var c checksum
+		err := binary.Read(d.r, binary.LittleEndian, &c)
+		return c, unexpected(err)
+	}
+
+	return nil, fmt.Errorf("unknown record type %d", kind[0])
+}
+
+// unexpected turns io.EOF into io.ErrUnexpectedEOF, a movie can only end
+// between two records
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package movie
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/libretro/ludo/input"
+	lr "github.com/libretro/ludo/libretro"
+)
+
+func Test_inputsFromStates(t *testing.T) {
+	t.Run("Keeps the game inputs and drops the hotkeys", func(t *testing.T) {
+		var s input.States
+		s[0][lr.DeviceIDJoypadA] = 1
+		s[2][lr.DeviceIDJoypadR3] = 1
+		s[0][input.ActionMenuToggle] = 1
+		var a input.AnalogStates
+		a[1][lr.DeviceIndexAnalogLeft][lr.DeviceIDAnalogY] = -32767
+
+		in := inputsFromStates(s, a)
+
+		var got input.States
+		var gotAnalog input.AnalogStates
+		got[3][input.ActionShouldClose] = 1
+		in.apply(&got, &gotAnalog)
+
+		want := s
+		want[0][input.ActionMenuToggle] = 0
+		want[3][input.ActionShouldClose] = 1
+		if got != want {
+			t.Errorf("got = %v, want %v", got, want)
+		}
+		if gotAnalog != a {
+			t.Errorf("got = %v, want %v", gotAnalog, a)
+		}
+	})
+}
+
+func Test_encoder(t *testing.T) {
+	h := Header{
+		Version:  Version,
+		Interval: 2,
+		Core:     "VecX",
+		Game:     "Polar Rescue (USA)",
+		State:    []byte{1, 2, 3, 4},
+	}
+	var in Inputs
+	in.Buttons[0] = 0x0101
+	in.Analog[4][1][0] = 1234
+
+	var buf bytes.Buffer
+	e := &encoder{&buf}
+	e.writeHeader(h)
+	e.writeInputs(Inputs{})
+	e.writeInputs(in)
+	e.writeChecksum(checksum{2, 0xdeadbeef})
+
+	t.Run("Can read back the header", func(t *testing.T) {
+		d := &decoder{bytes.NewReader(buf.Bytes())}
+		got, err := d.readHeader()
+		if err != nil {
+			t.Errorf("readHeader() = %v, want %v", err, nil)
+		}
+		if !reflect.DeepEqual(got, h) {
+			t.Errorf("got = %v, want %v", got, h)
+		}
+	})
+
+	t.Run("Can read back the records", func(t *testing.T) {
+		d := &decoder{bytes.NewReader(buf.Bytes())}
+		d.readHeader()
+		want := []interface{}{Inputs{}, in, checksum{2, 0xdeadbeef}}
+		for _, w := range want {
+			got, err := d.next()
+			if err != nil {
+				t.Errorf("next() = %v, want %v", err, nil)
+			}
+			if !reflect.DeepEqual(got, w) {
+				t.Errorf("got = %v, want %v", got, w)
+			}
+		}
+		_, err := d.next()
+		if err != io.EOF {
+			t.Errorf("next() = %v, want %v", err, io.EOF)
+		}
+	})
+
+	t.Run("Detects a truncated movie", func(t *testing.T) {
+		b := buf.Bytes()
+		d := &decoder{bytes.NewReader(b[:len(b)-3])}
+		d.readHeader()
+		d.next()
+		d.next()
+		_, err := d.next()
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("next() = %v, want %v", err, io.ErrUnexpectedEOF)
+		}
+	})
+
+	t.Run("Refuses files that are not movies", func(t *testing.T) {
+		d := &decoder{bytes.NewReader([]byte("PK\x03\x04 not a movie"))}
+		_, err := d.readHeader()
+		if err == nil {
+			t.Errorf("readHeader() = %v, want an error", err)
+		}
+	})
+}
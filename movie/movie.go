@@ -0,0 +1,285 @@
+// Package movie records the game inputs of all players frame by frame, and
+// plays them back deterministically. Movies start from power-on or from a
+// savestate, and embed periodic checksums of the core state so desyncs can
+// be detected during playback.
+package movie
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/libretro/ludo/input"
+	ntf "github.com/libretro/ludo/notifications"
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/utils"
+)
+
+// ChecksumInterval is the number of frames between two checksums of the core
+// state in newly recorded movies
+const ChecksumInterval = 60
+
+type session struct {
+	fd       *os.File
+	buf      *bufio.Writer
+	enc      *encoder // set when recording
+	dec      *decoder // set when playing back
+	interval uint32
+	frame    uint32
+	next     Inputs // inputs of the frame about to be played back
+	desync   bool
+}
+
+var current *session
+
+// Recording returns true if a movie is being recorded
+func Recording() bool {
+	return current != nil && current.enc != nil
+}
+
+// Playing returns true if a movie is being played back
+func Playing() bool {
+	return current != nil && current.dec != nil
+}
+
+// Active returns true if a movie is being recorded or played back
+func Active() bool {
+	return current != nil
+}
+
+// checksumState returns the CRC32 of the serialized core state
+func checksumState() (uint32, error) {
+	s := state.Core.SerializeSize()
+	bytes, err := state.Core.Serialize(s)
+	if err != nil {
+		return 0, err
+	}
+	return crc32.ChecksumIEEE(bytes), nil
+}
+
+// Record starts recording a movie for the running game in the movies
+// directory. If fromState is false, the core is reset first so the movie
+// starts from power-on. It returns the path of the movie.
+func Record(fromState bool) (string, error) {
+	if current != nil {
+		return "", errors.New("a movie is already active")
+	}
+
+	h := Header{
+		Version:  Version,
+		Interval: ChecksumInterval,
+		Core:     state.Core.GetSystemInfo().LibraryName,
+		Game:     utils.FileName(state.ContentPath()),
+	}
+	if fromState {
+		s := state.Core.SerializeSize()
+		bytes, err := state.Core.Serialize(s)
+		if err != nil {
+			return "", err
+		}
+		h.State = bytes
+	}
+
+	err := os.MkdirAll(settings.Current.MoviesDirectory, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(settings.Current.MoviesDirectory, utils.DatedName(state.ContentPath())+".movie")
+	fd, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+
+	buf := bufio.NewWriter(fd)
+	enc := &encoder{buf}
+	if err := enc.writeHeader(h); err != nil {
+		fd.Close()
+		return "", err
+	}
+
+	if !fromState {
+		state.Core.Reset()
+	}
+
+	current = &session{
+		fd:       fd,
+		buf:      buf,
+		enc:      enc,
+		interval: h.Interval,
+	}
+
+	return path, nil
+}
+
+// Play starts the playback of a movie for the running game. The core state
+// is restored from the movie, or the core is reset for power-on movies.
+func Play(path string) error {
+	if current != nil {
+		return errors.New("a movie is already active")
+	}
+
+	fd, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	dec := &decoder{bufio.NewReader(fd)}
+	h, err := dec.readHeader()
+	if err != nil {
+		fd.Close()
+		return err
+	}
+
+	err = h.check(
+		state.Core.GetSystemInfo().LibraryName,
+		utils.FileName(state.ContentPath()),
+		state.Core.SerializeSize(),
+	)
+	if err != nil {
+		fd.Close()
+		return err
+	}
+
+	if len(h.State) > 0 {
+		err = state.Core.Unserialize(h.State, uint(len(h.State)))
+		if err != nil {
+			fd.Close()
+			return err
+		}
+	} else {
+		state.Core.Reset()
+	}
+
+	current = &session{
+		fd:       fd,
+		dec:      dec,
+		interval: h.Interval,
+	}
+
+	if err := current.advance(); err != nil {
+		Stop()
+		return err
+	}
+
+	input.Replay = replay
+
+	return nil
+}
+
+// check refuses movies recorded with another core or another game, or
+// starting from a state the running core can't restore
+func (h Header) check(core, game string, stateSize uint) error {
+	if h.Core != core {
+		return fmt.Errorf("movie recorded with %s, not %s", h.Core, core)
+	}
+	if h.Game != game {
+		return fmt.Errorf("movie recorded for %s, not %s", h.Game, game)
+	}
+	if len(h.State) > 0 && uint(len(h.State)) != stateSize {
+		return fmt.Errorf("movie state is %d bytes, the core expects %d", len(h.State), stateSize)
+	}
+	return nil
+}
+
+// replay is plugged into input.Poll during playback
+func replay(s *input.States, a *input.AnalogStates) {
+	// Let the user navigate the menu
+	if state.MenuActive || current == nil {
+		return
+	}
+	current.next.apply(s, a)
+}
+
+// advance reads the movie up to the inputs of the next frame, verifying the
+// checksums found on the way
+func (m *session) advance() error {
+	for {
+		r, err := m.dec.next()
+		if err != nil {
+			return err
+		}
+
+		switch r := r.(type) {
+		case Inputs:
+			m.next = r
+			return nil
+		case checksum:
+			if m.desync || r.Frame != m.frame {
+				continue
+			}
+			sum, err := checksumState()
+			if err != nil {
+				return err
+			}
+			if sum != r.Sum {
+				m.desync = true
+				ntf.DisplayAndLog(ntf.Warning, "Movie", "Desync detected at frame %d.", m.frame)
+			}
+		}
+	}
+}
+
+// Frame has to be called after each frame run by the core. It records the
+// inputs of the frame, or moves the playback to the next frame.
+func Frame() error {
+	if current == nil {
+		return nil
+	}
+
+	m := current
+	if m.enc != nil {
+		in := inputsFromStates(input.NewState, input.NewAnalogState)
+		if err := m.enc.writeInputs(in); err != nil {
+			Stop()
+			return err
+		}
+		m.frame++
+		if m.interval > 0 && m.frame%m.interval == 0 {
+			sum, err := checksumState()
+			if err != nil {
+				Stop()
+				return err
+			}
+			if err := m.enc.writeChecksum(checksum{m.frame, sum}); err != nil {
+				Stop()
+				return err
+			}
+		}
+		return nil
+	}
+
+	m.frame++
+	err := m.advance()
+	if err == io.EOF {
+		ntf.DisplayAndLog(ntf.Info, "Movie", "Playback finished after %d frames.", m.frame)
+		return Stop()
+	}
+	if err != nil {
+		Stop()
+	}
+	return err
+}
+
+// Stop ends the recording or the playback of the current movie
+func Stop() error {
+	if current == nil {
+		return nil
+	}
+
+	m := current
+	current = nil
+	input.Replay = nil
+
+	if m.buf != nil {
+		if err := m.buf.Flush(); err != nil {
+			m.fd.Close()
+			return err
+		}
+	}
+	return m.fd.Close()
+}
@@ -0,0 +1,25 @@
+package movie
+
+import "testing"
+
+func TestHeader_check(t *testing.T) {
+	tests := []struct {
+		name    string
+		h       Header
+		wantErr bool
+	}{
+		{"Accepts a power-on movie", Header{Core: "Snes9x", Game: "Mario"}, false},
+		{"Accepts a state of the right size", Header{Core: "Snes9x", Game: "Mario", State: make([]byte, 4)}, false},
+		{"Refuses another core", Header{Core: "bsnes", Game: "Mario"}, true},
+		{"Refuses another game", Header{Core: "Snes9x", Game: "Zelda"}, true},
+		{"Refuses a truncated state", Header{Core: "Snes9x", Game: "Mario", State: make([]byte, 2)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.h.check("Snes9x", "Mario", 4)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package patch
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math"
+)
+
+// bpsMaxTargetSize caps the memory allocated for the patched game, whatever
+// size the patch declares
+const bpsMaxTargetSize = 1 << 30
+
+// bpsMaxVarintLen is the length of the longest number accepted in a patch
+const bpsMaxVarintLen = 9
+
+// BPS actions
+const (
+	bpsSourceRead = iota
+	bpsTargetRead
+	bpsSourceCopy
+	bpsTargetCopy
+)
+
+type bpsReader struct {
+	data   []byte
+	offset int
+}
+
+func (r *bpsReader) read() (byte, error) {
+	if r.offset >= len(r.data) {
+		return 0, errors.New("invalid patch")
+	}
+	n := r.data[r.offset]
+	r.offset++
+	return n, nil
+}
+
+// decode reads a variable length number, the encoding is the same as UPS.
+// Numbers that don't fit in an int32 are refused, so crafted patches can't
+// wrap the offsets and lengths around.
+func (r *bpsReader) decode() (int, error) {
+	var data = 0
+	var shift = 1
+	for i := 0; ; i++ {
+		if i == bpsMaxVarintLen {
+			return 0, errors.New("invalid patch")
+		}
+		x, err := r.read()
+		if err != nil {
+			return 0, err
+		}
+		data += int(x&0x7f) * shift
+		if data > math.MaxInt32 {
+			return 0, errors.New("invalid patch")
+		}
+		if x&0x80 != 0 {
+			break
+		}
+		shift <<= 7
+		data += shift
+		if data > math.MaxInt32 {
+			return 0, errors.New("invalid patch")
+		}
+	}
+	return data, nil
+}
+
+// decodeSigned reads a relative offset
+func (r *bpsReader) decodeSigned() (int, error) {
+	data, err := r.decode()
+	if err != nil {
+		return 0, err
+	}
+	if data&1 != 0 {
+		return -(data >> 1), nil
+	}
+	return data >> 1, nil
+}
+
+func applyBPS(patch, source []byte) (*[]byte, error) {
+	if len(patch) < 19 {
+		return nil, errors.New("patch too small")
+	}
+
+	if string(patch[0:4]) != "BPS1" {
+		return nil, errors.New("invalid patch header")
+	}
+
+	footer := len(patch) - 12
+	sourceChecksum := binary.LittleEndian.Uint32(patch[footer:])
+	targetChecksum := binary.LittleEndian.Uint32(patch[footer+4:])
+	patchChecksum := binary.LittleEndian.Uint32(patch[footer+8:])
+
+	if crc32.ChecksumIEEE(patch[:footer+8]) != patchChecksum {
+		return nil, errors.New("invalid patch")
+	}
+
+	r := &bpsReader{data: patch[:footer], offset: 4}
+
+	sourceSize, err := r.decode()
+	if err != nil {
+		return nil, err
+	}
+	targetSize, err := r.decode()
+	if err != nil {
+		return nil, err
+	}
+	metadataSize, err := r.decode()
+	if err != nil {
+		return nil, err
+	}
+	if metadataSize > len(r.data)-r.offset {
+		return nil, errors.New("invalid patch")
+	}
+	r.offset += metadataSize
+
+	if targetSize > bpsMaxTargetSize {
+		return nil, errors.New("target too big")
+	}
+
+	if sourceSize != len(source) || crc32.ChecksumIEEE(source) != sourceChecksum {
+		return nil, errors.New("invalid source")
+	}
+
+	target := make([]byte, targetSize)
+	outputOffset := 0
+	sourceRelativeOffset := 0
+	targetRelativeOffset := 0
+
+	for r.offset < len(r.data) {
+		data, err := r.decode()
+		if err != nil {
+			return nil, err
+		}
+		command := data & 3
+		length := (data >> 2) + 1
+
+		if length <= 0 || outputOffset+length > targetSize {
+			return nil, errors.New("invalid patch")
+		}
+
+		switch command {
+		case bpsSourceRead:
+			if outputOffset+length > len(source) {
+				return nil, errors.New("invalid patch")
+			}
+			copy(target[outputOffset:], source[outputOffset:outputOffset+length])
+			outputOffset += length
+		case bpsTargetRead:
+			if r.offset+length > len(r.data) {
+				return nil, errors.New("invalid patch")
+			}
+			copy(target[outputOffset:], r.data[r.offset:r.offset+length])
+			r.offset += length
+			outputOffset += length
+		case bpsSourceCopy:
+			rel, err := r.decodeSigned()
+			if err != nil {
+				return nil, err
+			}
+			sourceRelativeOffset += rel
+			if sourceRelativeOffset < 0 || sourceRelativeOffset+length > len(source) {
+				return nil, errors.New("invalid patch")
+			}
+			copy(target[outputOffset:], source[sourceRelativeOffset:sourceRelativeOffset+length])
+			sourceRelativeOffset += length
+			outputOffset += length
+		case bpsTargetCopy:
+			rel, err := r.decodeSigned()
+			if err != nil {
+				return nil, err
+			}
+			targetRelativeOffset += rel
+			if targetRelativeOffset < 0 || targetRelativeOffset >= outputOffset {
+				return nil, errors.New("invalid patch")
+			}
+			// The areas can overlap, so copy byte by byte
+			for ; length > 0; length-- {
+				target[outputOffset] = target[targetRelativeOffset]
+				outputOffset++
+				targetRelativeOffset++
+			}
+		}
+	}
+
+	if outputOffset != targetSize || crc32.ChecksumIEEE(target) != targetChecksum {
+		return nil, errors.New("invalid target")
+	}
+
+	return &target, nil
+}
@@ -0,0 +1,166 @@
+package patch
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func bpsEncode(n int) []byte {
+	out := []byte{}
+	for {
+		x := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 {
+			return append(out, 0x80|x)
+		}
+		out = append(out, x)
+		n--
+	}
+}
+
+func bpsAction(command, length int) []byte {
+	return bpsEncode((length-1)<<2 | command)
+}
+
+func bpsRelative(n int) []byte {
+	if n < 0 {
+		return bpsEncode(-n<<1 | 1)
+	}
+	return bpsEncode(n << 1)
+}
+
+func bpsPatch(source, target []byte, actions ...[]byte) []byte {
+	header := append(bpsEncode(len(source)), bpsEncode(len(target))...)
+	header = append(header, bpsEncode(0)...)
+	return bpsRaw(source, target, append([][]byte{header}, actions...)...)
+}
+
+// bpsRaw builds a patch with valid checksums around any content
+func bpsRaw(source, target []byte, content ...[]byte) []byte {
+	p := []byte("BPS1")
+	for _, c := range content {
+		p = append(p, c...)
+	}
+	p = appendUint32(p, crc32.ChecksumIEEE(source))
+	p = appendUint32(p, crc32.ChecksumIEEE(target))
+	return appendUint32(p, crc32.ChecksumIEEE(p))
+}
+
+func appendUint32(p []byte, n uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, n)
+	return append(p, b...)
+}
+
+func Test_applyBPS(t *testing.T) {
+	source := []byte("Hello World!")
+	target := []byte("Hello Ludo! Ludo!")
+	patch := bpsPatch(source, target,
+		bpsAction(bpsSourceRead, 6),
+		append(bpsAction(bpsTargetRead, 4), "Ludo"...),
+		append(bpsAction(bpsSourceCopy, 1), bpsRelative(11)...),
+		append(bpsAction(bpsTargetCopy, 6), bpsRelative(5)...),
+	)
+
+	t.Run("Can apply a valid BPS patch", func(t *testing.T) {
+		got, err := applyBPS(patch, source)
+		if err != nil {
+			t.Errorf("applyBPS() = %v, want %v", err, nil)
+		}
+		if got == nil || !reflect.DeepEqual(*got, target) {
+			t.Errorf("applyBPS() = %v, want %v", got, target)
+		}
+	})
+
+	t.Run("Can detect a short patch", func(t *testing.T) {
+		got, err := applyBPS([]byte("BPS1"), source)
+		if err.Error() != "patch too small" {
+			t.Errorf("applyBPS() = %v, want %v", err, "patch too small")
+		}
+		if got != nil {
+			t.Errorf("applyBPS() = %v, want %v", got, nil)
+		}
+	})
+
+	t.Run("Can detect a patch with a wrong header", func(t *testing.T) {
+		p := append([]byte("UPS1"), patch[4:]...)
+		got, err := applyBPS(p, source)
+		if err.Error() != "invalid patch header" {
+			t.Errorf("applyBPS() = %v, want %v", err, "invalid patch header")
+		}
+		if got != nil {
+			t.Errorf("applyBPS() = %v, want %v", got, nil)
+		}
+	})
+
+	t.Run("Can detect a corrupted patch", func(t *testing.T) {
+		p := append([]byte{}, patch...)
+		p[10] ^= 0xff
+		got, err := applyBPS(p, source)
+		if err.Error() != "invalid patch" {
+			t.Errorf("applyBPS() = %v, want %v", err, "invalid patch")
+		}
+		if got != nil {
+			t.Errorf("applyBPS() = %v, want %v", got, nil)
+		}
+	})
+
+	t.Run("Can detect a wrong source", func(t *testing.T) {
+		got, err := applyBPS(patch, []byte("Hello world!"))
+		if err.Error() != "invalid source" {
+			t.Errorf("applyBPS() = %v, want %v", err, "invalid source")
+		}
+		if got != nil {
+			t.Errorf("applyBPS() = %v, want %v", got, nil)
+		}
+	})
+
+	t.Run("Can detect a wrong target", func(t *testing.T) {
+		p := bpsPatch(source, []byte("Hello Ludo! Ludo?"),
+			bpsAction(bpsSourceRead, 6),
+			append(bpsAction(bpsTargetRead, 4), "Ludo"...),
+			append(bpsAction(bpsSourceCopy, 1), bpsRelative(11)...),
+			append(bpsAction(bpsTargetCopy, 6), bpsRelative(5)...),
+		)
+		got, err := applyBPS(p, source)
+		if err.Error() != "invalid target" {
+			t.Errorf("applyBPS() = %v, want %v", err, "invalid target")
+		}
+		if got != nil {
+			t.Errorf("applyBPS() = %v, want %v", got, nil)
+		}
+	})
+
+	t.Run("Can detect numbers that overflow", func(t *testing.T) {
+		// A metadata size of 10 bytes would wrap the offset around
+		overflow := []byte{0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0xff}
+		tests := [][]byte{
+			append(append(bpsEncode(len(source)), bpsEncode(len(target))...), overflow...),
+			append(append(bpsEncode(len(source)), bpsEncode(len(target))...), bpsEncode(1<<20)...),
+			append(append(bpsEncode(len(source)), bpsEncode(len(target))...), append(bpsEncode(0), overflow...)...),
+		}
+		for _, content := range tests {
+			got, err := applyBPS(bpsRaw(source, target, content), source)
+			if err == nil || err.Error() != "invalid patch" {
+				t.Errorf("applyBPS() = %v, want %v", err, "invalid patch")
+			}
+			if got != nil {
+				t.Errorf("applyBPS() = %v, want %v", got, nil)
+			}
+		}
+	})
+
+	t.Run("Can detect a target too big", func(t *testing.T) {
+		content := append(append(bpsEncode(len(source)), bpsEncode(math.MaxInt32)...), bpsEncode(0)...)
+		got, err := applyBPS(bpsRaw(source, target, content), source)
+		if err == nil || err.Error() != "target too big" {
+			t.Errorf("applyBPS() = %v, want %v", err, "target too big")
+		}
+		if got != nil {
+			t.Errorf("applyBPS() = %v, want %v", got, nil)
+		}
+	})
+}
@@ -1,47 +1,202 @@
 // Package patch allows softpatching ROMs based on the presence of a patch file
 // next to the ROM. This is useful to apply fan translations without altering
 // No-Intro ROMs. Softpatching only works for cores where NeedFullPath is false.
+//
+// Several patches can be stacked by numbering them: game.1.ips, game.2.bps...
+// They are applied in order. An unnumbered patch like game.ips is applied
+// first. Patches are looked up next to the ROM, in the patches directory, and
+// in a subdirectory of the patches directory named after the game.
 package patch
 
 import (
+	"fmt"
 	"io/ioutil"
-	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/utils"
 )
 
-// Try to apply different patches located next to the game
-// Currently only .ups is supported
-func Try(gamePath string, bytes []byte) (*[]byte, error) {
-	upsFile := strings.TrimSuffix(gamePath, filepath.Ext(gamePath)) + ".ups"
-	if _, err := os.Stat(upsFile); !os.IsNotExist(err) {
-		pbytes, err := ioutil.ReadFile(upsFile)
-		if err != nil {
-			return nil, err
+// formats lists the supported patch formats by order of preference
+var formats = []struct {
+	ext   string
+	apply func(patch, source []byte) (*[]byte, error)
+}{
+	{".ups", applyUPS},
+	{".bps", applyBPS},
+	{".ips", applyIPS},
+	{".xdelta", applyVCDIFF},
+	{".vcdiff", applyVCDIFF},
+}
+
+// applied is the list of patches applied to the running game
+var applied []string
+
+// Applied returns the paths of the patches applied to the running game
+func Applied() []string {
+	return applied
+}
+
+// Reset forgets the patches applied to the running game
+func Reset() {
+	applied = nil
+}
+
+// formatIndex returns the preference order of a patch extension, or -1 if the
+// format isn't supported
+func formatIndex(ext string) int {
+	for i, f := range formats {
+		if strings.EqualFold(f.ext, ext) {
+			return i
+		}
+	}
+	return -1
+}
+
+// patchOrder parses the file name of a patch for the given game name. It
+// returns the position of the patch in the stack, or false if the file isn't
+// a patch for this game.
+func patchOrder(name, file string) (int, bool) {
+	if !strings.HasPrefix(file, name+".") {
+		return 0, false
+	}
+	ext := filepath.Ext(file)
+	if formatIndex(ext) < 0 {
+		return 0, false
+	}
+
+	middle := strings.TrimSuffix(strings.TrimPrefix(file, name), ext)
+	if middle == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(middle, "."))
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	return n, true
+}
+
+// candidate is a patch file found for a game
+type candidate struct {
+	path   string
+	order  int // position in the stack
+	dir    int // index of the directory where it was found
+	format int // index in formats
+}
+
+// sortPatches sorts and deduplicates a list of candidate patches. Only one
+// patch is kept for a given position in the stack, first by directory order
+// then by format preference.
+func sortPatches(candidates []candidate) []string {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.order != b.order {
+			return a.order < b.order
+		}
+		if a.dir != b.dir {
+			return a.dir < b.dir
+		}
+		return a.format < b.format
+	})
+
+	paths := []string{}
+	for i, c := range candidates {
+		if i > 0 && candidates[i-1].order == c.order {
+			continue
 		}
+		paths = append(paths, c.path)
+	}
+	return paths
+}
 
-		patched, err := applyUPS(pbytes, bytes)
+// Find returns the patches found for a game, in the order they will be
+// applied, including the disabled ones
+func Find(gamePath string) []string {
+	name := utils.FileName(gamePath)
+	dirs := []string{filepath.Dir(gamePath)}
+	if settings.Current.PatchesDirectory != "" {
+		dirs = append(dirs,
+			settings.Current.PatchesDirectory,
+			filepath.Join(settings.Current.PatchesDirectory, name))
+	}
+
+	candidates := []candidate{}
+	for i, dir := range dirs {
+		// Not using filepath.Glob here, ROM names often contain brackets
+		infos, err := ioutil.ReadDir(dir)
 		if err != nil {
-			return nil, err
+			continue
 		}
+		for _, info := range infos {
+			if info.IsDir() {
+				continue
+			}
+			if n, ok := patchOrder(name, info.Name()); ok {
+				candidates = append(candidates, candidate{
+					path:   filepath.Join(dir, info.Name()),
+					order:  n,
+					dir:    i,
+					format: formatIndex(filepath.Ext(info.Name())),
+				})
+			}
+		}
+	}
 
-		return patched, nil
+	return sortPatches(candidates)
+}
+
+// Enabled returns true if the patch hasn't been disabled by the user
+func Enabled(path string) bool {
+	return !settings.Current.DisabledPatches[path]
+}
+
+// SetEnabled enables or disables a patch for the next loads of the game
+func SetEnabled(path string, enabled bool) error {
+	if settings.Current.DisabledPatches == nil {
+		settings.Current.DisabledPatches = map[string]bool{}
 	}
+	if enabled {
+		delete(settings.Current.DisabledPatches, path)
+	} else {
+		settings.Current.DisabledPatches[path] = true
+	}
+	return settings.Save()
+}
 
-	ipsFile := strings.TrimSuffix(gamePath, filepath.Ext(gamePath)) + ".ips"
-	if _, err := os.Stat(ipsFile); !os.IsNotExist(err) {
-		pbytes, err := ioutil.ReadFile(ipsFile)
+// Try to apply the enabled patches found for the game, in order.
+// Supported formats are .ups, .bps, .ips and xdelta/VCDIFF.
+// It returns nil if no patch was applied. If a patch fails to apply, none of
+// the patches are applied and the error names the faulty patch.
+func Try(gamePath string, bytes []byte) (*[]byte, error) {
+	applied = nil
+
+	patched := &bytes
+	list := []string{}
+	for _, path := range Find(gamePath) {
+		if !Enabled(path) {
+			continue
+		}
+
+		pbytes, err := ioutil.ReadFile(path)
 		if err != nil {
 			return nil, err
 		}
 
-		patched, err := applyIPS(pbytes, bytes)
+		apply := formats[formatIndex(filepath.Ext(path))].apply
+		patched, err = apply(pbytes, *patched)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%s: %v", filepath.Base(path), err)
 		}
+		list = append(list, path)
+	}
 
-		return patched, nil
+	if len(list) == 0 {
+		return nil, nil
 	}
 
-	return nil, nil
+	applied = list
+	return patched, nil
 }
@@ -0,0 +1,120 @@
+package patch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/libretro/ludo/settings"
+)
+
+func Test_patchOrder(t *testing.T) {
+	tests := []struct {
+		file  string
+		order int
+		ok    bool
+	}{
+		{"Game (USA) [!].ips", 0, true},
+		{"Game (USA) [!].2.bps", 2, true},
+		{"Game (USA) [!].10.UPS", 10, true},
+		{"Game (USA) [!].sfc", 0, false},
+		{"Game (USA) [!].v1.ips", 0, false},
+		{"Game (USA) [!].0.ips", 0, false},
+		{"Game (Europe).ips", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			order, ok := patchOrder("Game (USA) [!]", tt.file)
+			if order != tt.order || ok != tt.ok {
+				t.Errorf("patchOrder() = %v, %v, want %v, %v", order, ok, tt.order, tt.ok)
+			}
+		})
+	}
+}
+
+func Test_Try(t *testing.T) {
+	games, err := ioutil.TempDir("", "games")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(games)
+	patches, err := ioutil.TempDir("", "patches")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(patches)
+	settings.Current.PatchesDirectory = patches
+	settings.Current.DisabledPatches = map[string]bool{}
+
+	source := []byte("Hello World!")
+	step1 := []byte("Hello Ludo!!")
+	step2 := []byte("Hello Ludo!! Bye.")
+
+	write := func(path string, data []byte) {
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gamePath := filepath.Join(games, "Game [!].sfc")
+	write(gamePath, source)
+	write(filepath.Join(games, "Game [!].1.bps"), bpsPatch(source, step1,
+		bpsAction(bpsSourceRead, 6),
+		append(bpsAction(bpsTargetRead, 6), "Ludo!!"...),
+	))
+	write(filepath.Join(patches, "Game [!].2.bps"), bpsPatch(step1, step2,
+		bpsAction(bpsSourceRead, 12),
+		append(bpsAction(bpsTargetRead, 5), " Bye."...),
+	))
+	// Same position as the first patch, the one next to the game wins
+	write(filepath.Join(patches, "Game [!].1.ips"), []byte("PATCHEOF"))
+
+	t.Run("Finds the patches in order", func(t *testing.T) {
+		got := Find(gamePath)
+		want := []string{
+			filepath.Join(games, "Game [!].1.bps"),
+			filepath.Join(patches, "Game [!].2.bps"),
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Applies stacked patches", func(t *testing.T) {
+		got, err := Try(gamePath, source)
+		if err != nil {
+			t.Errorf("Try() = %v, want %v", err, nil)
+		}
+		if got == nil || !reflect.DeepEqual(*got, step2) {
+			t.Errorf("Try() = %v, want %v", got, step2)
+		}
+		if len(Applied()) != 2 {
+			t.Errorf("got = %v, want %v", len(Applied()), 2)
+		}
+	})
+
+	t.Run("Skips disabled patches", func(t *testing.T) {
+		settings.Current.DisabledPatches[filepath.Join(patches, "Game [!].2.bps")] = true
+		defer delete(settings.Current.DisabledPatches, filepath.Join(patches, "Game [!].2.bps"))
+		got, err := Try(gamePath, source)
+		if err != nil {
+			t.Errorf("Try() = %v, want %v", err, nil)
+		}
+		if got == nil || !reflect.DeepEqual(*got, step1) {
+			t.Errorf("Try() = %v, want %v", got, step1)
+		}
+	})
+
+	t.Run("Reports the patch failing its checksum", func(t *testing.T) {
+		settings.Current.DisabledPatches[filepath.Join(games, "Game [!].1.bps")] = true
+		defer delete(settings.Current.DisabledPatches, filepath.Join(games, "Game [!].1.bps"))
+		got, err := Try(gamePath, source)
+		if err == nil || err.Error() != "Game [!].2.bps: invalid source" {
+			t.Errorf("Try() = %v, want %v", err, "Game [!].2.bps: invalid source")
+		}
+		if got != nil || Applied() != nil {
+			t.Errorf("Try() = %v, want %v", got, nil)
+		}
+	})
+}
@@ -0,0 +1,361 @@
+package patch
+
+import (
+	"errors"
+	"hash/adler32"
+	"math"
+)
+
+// VCDIFF (RFC 3284) decoder, as produced by xdelta3. Secondary compression
+// and custom code tables are not supported, patches have to be created with
+// xdelta3 -S none.
+
+// Header indicator bits
+const (
+	vcdDecompress = 1 << iota
+	vcdCodetable
+	vcdAppheader
+)
+
+// Window indicator bits
+const (
+	vcdSource = 1 << iota
+	vcdTarget
+	vcdAdler32 // xdelta3 extension
+)
+
+// Instruction types
+const (
+	vcdNoop = iota
+	vcdAdd
+	vcdRun
+	vcdCopy
+)
+
+const (
+	vcdNearSize = 4
+	vcdSameSize = 3
+)
+
+type vcdInstruction struct {
+	kind byte
+	size int
+	mode byte
+}
+
+// vcdCodeTable is the default code table described in section 5.6 of the RFC
+var vcdCodeTable = func() [256][2]vcdInstruction {
+	var t [256][2]vcdInstruction
+	i := 0
+
+	t[i][0] = vcdInstruction{kind: vcdRun}
+	i++
+
+	for size := 0; size <= 17; size++ {
+		t[i][0] = vcdInstruction{kind: vcdAdd, size: size}
+		i++
+	}
+
+	for mode := byte(0); mode < 9; mode++ {
+		t[i][0] = vcdInstruction{kind: vcdCopy, mode: mode}
+		i++
+		for size := 4; size <= 18; size++ {
+			t[i][0] = vcdInstruction{kind: vcdCopy, size: size, mode: mode}
+			i++
+		}
+	}
+
+	for mode := byte(0); mode < 9; mode++ {
+		maxCopy := 6
+		if mode >= 6 {
+			maxCopy = 4
+		}
+		for add := 1; add <= 4; add++ {
+			for size := 4; size <= maxCopy; size++ {
+				t[i][0] = vcdInstruction{kind: vcdAdd, size: add}
+				t[i][1] = vcdInstruction{kind: vcdCopy, size: size, mode: mode}
+				i++
+			}
+		}
+	}
+
+	for mode := byte(0); mode < 9; mode++ {
+		t[i][0] = vcdInstruction{kind: vcdCopy, size: 4, mode: mode}
+		t[i][1] = vcdInstruction{kind: vcdAdd, size: 1}
+		i++
+	}
+
+	return t
+}()
+
+type vcdReader struct {
+	data   []byte
+	offset int
+}
+
+func (r *vcdReader) byte() (byte, error) {
+	if r.offset >= len(r.data) {
+		return 0, errors.New("invalid patch")
+	}
+	n := r.data[r.offset]
+	r.offset++
+	return n, nil
+}
+
+// int reads a big endian base 128 integer. Values that don't fit in an int32
+// are rejected, so sums of sizes and offsets can't overflow.
+func (r *vcdReader) int() (int, error) {
+	var n int
+	for i := 0; i < 5; i++ {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		n = n<<7 | int(b&0x7f)
+		if n > math.MaxInt32 {
+			return 0, errors.New("invalid patch")
+		}
+		if b&0x80 == 0 {
+			return n, nil
+		}
+	}
+	return 0, errors.New("invalid patch")
+}
+
+func (r *vcdReader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(r.data)-r.offset {
+		return nil, errors.New("invalid patch")
+	}
+	b := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return b, nil
+}
+
+type vcdAddressCache struct {
+	near     [vcdNearSize]int
+	nextSlot int
+	same     [vcdSameSize * 256]int
+}
+
+func (c *vcdAddressCache) update(addr int) {
+	c.near[c.nextSlot] = addr
+	c.nextSlot = (c.nextSlot + 1) % vcdNearSize
+	c.same[addr%(vcdSameSize*256)] = addr
+}
+
+func (c *vcdAddressCache) decode(addrs *vcdReader, here int, mode byte) (int, error) {
+	var addr int
+	switch {
+	case mode == 0:
+		n, err := addrs.int()
+		if err != nil {
+			return 0, err
+		}
+		addr = n
+	case mode == 1:
+		n, err := addrs.int()
+		if err != nil {
+			return 0, err
+		}
+		addr = here - n
+	case mode-2 < vcdNearSize:
+		n, err := addrs.int()
+		if err != nil {
+			return 0, err
+		}
+		addr = c.near[mode-2] + n
+	default:
+		b, err := addrs.byte()
+		if err != nil {
+			return 0, err
+		}
+		addr = c.same[int(mode-2-vcdNearSize)*256+int(b)]
+	}
+	c.update(addr)
+	return addr, nil
+}
+
+func applyVCDIFF(patch, source []byte) (*[]byte, error) {
+	if len(patch) < 5 {
+		return nil, errors.New("patch too small")
+	}
+
+	if patch[0] != 0xd6 || patch[1] != 0xc3 || patch[2] != 0xc4 || patch[3] != 0 {
+		return nil, errors.New("invalid patch header")
+	}
+
+	r := &vcdReader{data: patch, offset: 4}
+	indicator, _ := r.byte()
+	if indicator&vcdDecompress != 0 {
+		return nil, errors.New("secondary compression not supported")
+	}
+	if indicator&vcdCodetable != 0 {
+		return nil, errors.New("custom code table not supported")
+	}
+	if indicator&vcdAppheader != 0 {
+		n, err := r.int()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := r.bytes(n); err != nil {
+			return nil, err
+		}
+	}
+
+	target := []byte{}
+	for r.offset < len(r.data) {
+		window, err := vcdWindow(r, source, target)
+		if err != nil {
+			return nil, err
+		}
+		target = append(target, window...)
+	}
+
+	return &target, nil
+}
+
+// vcdWindow decodes a single window. target is the output decoded so far.
+func vcdWindow(r *vcdReader, source, target []byte) ([]byte, error) {
+	indicator, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	var segment []byte
+	if indicator&(vcdSource|vcdTarget) != 0 {
+		size, err := r.int()
+		if err != nil {
+			return nil, err
+		}
+		pos, err := r.int()
+		if err != nil {
+			return nil, err
+		}
+		from := source
+		if indicator&vcdTarget != 0 {
+			from = target
+		}
+		if pos > len(from) || size > len(from)-pos {
+			return nil, errors.New("invalid source")
+		}
+		segment = from[pos : pos+size]
+	}
+
+	// Length of the delta encoding, not needed
+	if _, err := r.int(); err != nil {
+		return nil, err
+	}
+	windowSize, err := r.int()
+	if err != nil {
+		return nil, err
+	}
+	if windowSize > bpsMaxTargetSize-len(target) {
+		return nil, errors.New("target too big")
+	}
+	deltaIndicator, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	if deltaIndicator != 0 {
+		return nil, errors.New("secondary compression not supported")
+	}
+
+	var lengths [3]int
+	for i := range lengths {
+		if lengths[i], err = r.int(); err != nil {
+			return nil, err
+		}
+	}
+
+	var checksum uint32
+	if indicator&vcdAdler32 != 0 {
+		b, err := r.bytes(4)
+		if err != nil {
+			return nil, err
+		}
+		checksum = uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+	}
+
+	dataSection, err := r.bytes(lengths[0])
+	if err != nil {
+		return nil, err
+	}
+	instSection, err := r.bytes(lengths[1])
+	if err != nil {
+		return nil, err
+	}
+	addrSection, err := r.bytes(lengths[2])
+	if err != nil {
+		return nil, err
+	}
+
+	data := &vcdReader{data: dataSection}
+	insts := &vcdReader{data: instSection}
+	addrs := &vcdReader{data: addrSection}
+	cache := &vcdAddressCache{}
+
+	out := []byte{}
+	for insts.offset < len(insts.data) {
+		code, _ := insts.byte()
+		for _, inst := range vcdCodeTable[code] {
+			if inst.kind == vcdNoop {
+				continue
+			}
+
+			size := inst.size
+			if size == 0 {
+				if size, err = insts.int(); err != nil {
+					return nil, err
+				}
+			}
+			if size > windowSize-len(out) {
+				return nil, errors.New("invalid patch")
+			}
+
+			switch inst.kind {
+			case vcdAdd:
+				b, err := data.bytes(size)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, b...)
+			case vcdRun:
+				b, err := data.byte()
+				if err != nil {
+					return nil, err
+				}
+				for ; size > 0; size-- {
+					out = append(out, b)
+				}
+			case vcdCopy:
+				here := len(segment) + len(out)
+				addr, err := cache.decode(addrs, here, inst.mode)
+				if err != nil {
+					return nil, err
+				}
+				if addr < 0 || addr >= here {
+					return nil, errors.New("invalid patch")
+				}
+				// The copy can overlap the bytes being written, so copy byte
+				// by byte
+				for ; size > 0; size-- {
+					if addr < len(segment) {
+						out = append(out, segment[addr])
+					} else {
+						out = append(out, out[addr-len(segment)])
+					}
+					addr++
+				}
+			}
+		}
+	}
+
+	if len(out) != windowSize {
+		return nil, errors.New("invalid target")
+	}
+	if indicator&vcdAdler32 != 0 && adler32.Checksum(out) != checksum {
+		return nil, errors.New("invalid target")
+	}
+
+	return out, nil
+}
@@ -0,0 +1,116 @@
+package patch
+
+import (
+	"hash/adler32"
+	"reflect"
+	"testing"
+)
+
+// vcdiffPatch builds a single window VCDIFF patch using source as the source
+// segment. All the lengths must be smaller than 128.
+func vcdiffPatch(source, target, data, inst, addr []byte) []byte {
+	sum := adler32.Checksum(target)
+	delta := []byte{byte(len(target)), 0, byte(len(data)), byte(len(inst)), byte(len(addr)),
+		byte(sum >> 24), byte(sum >> 16), byte(sum >> 8), byte(sum)}
+	delta = append(delta, data...)
+	delta = append(delta, inst...)
+	delta = append(delta, addr...)
+
+	p := []byte{0xd6, 0xc3, 0xc4, 0, 0}
+	p = append(p, vcdSource|vcdAdler32, byte(len(source)), 0, byte(len(delta)))
+	return append(p, delta...)
+}
+
+func Test_applyVCDIFF(t *testing.T) {
+	source := []byte("Hello World!")
+	target := []byte("Hello Ludo! Ludo!???")
+	data := []byte("Ludo?")
+	inst := []byte{
+		22,    // COPY 6 bytes, mode SELF
+		5,     // ADD 4 bytes
+		19, 1, // COPY 1 byte, mode SELF
+		38,   // COPY 6 bytes, mode HERE
+		0, 3, // RUN 3 bytes
+	}
+	addr := []byte{0, 11, 6}
+	patch := vcdiffPatch(source, target, data, inst, addr)
+
+	t.Run("Can apply a valid VCDIFF patch", func(t *testing.T) {
+		got, err := applyVCDIFF(patch, source)
+		if err != nil {
+			t.Errorf("applyVCDIFF() = %v, want %v", err, nil)
+		}
+		if got == nil || !reflect.DeepEqual(*got, target) {
+			t.Errorf("applyVCDIFF() = %v, want %v", got, target)
+		}
+	})
+
+	t.Run("Can detect a short patch", func(t *testing.T) {
+		got, err := applyVCDIFF([]byte{0xd6, 0xc3}, source)
+		if err.Error() != "patch too small" {
+			t.Errorf("applyVCDIFF() = %v, want %v", err, "patch too small")
+		}
+		if got != nil {
+			t.Errorf("applyVCDIFF() = %v, want %v", got, nil)
+		}
+	})
+
+	t.Run("Can detect a patch with a wrong header", func(t *testing.T) {
+		got, err := applyVCDIFF([]byte("PATCH...EOF"), source)
+		if err.Error() != "invalid patch header" {
+			t.Errorf("applyVCDIFF() = %v, want %v", err, "invalid patch header")
+		}
+		if got != nil {
+			t.Errorf("applyVCDIFF() = %v, want %v", got, nil)
+		}
+	})
+
+	t.Run("Can detect secondary compression", func(t *testing.T) {
+		p := append([]byte{}, patch...)
+		p[4] = vcdDecompress
+		got, err := applyVCDIFF(p, source)
+		if err.Error() != "secondary compression not supported" {
+			t.Errorf("applyVCDIFF() = %v, want %v", err, "secondary compression not supported")
+		}
+		if got != nil {
+			t.Errorf("applyVCDIFF() = %v, want %v", got, nil)
+		}
+	})
+
+	t.Run("Can detect a wrong source", func(t *testing.T) {
+		got, err := applyVCDIFF(patch, []byte("Howdy World!"))
+		if err.Error() != "invalid target" {
+			t.Errorf("applyVCDIFF() = %v, want %v", err, "invalid target")
+		}
+		if got != nil {
+			t.Errorf("applyVCDIFF() = %v, want %v", got, nil)
+		}
+	})
+	t.Run("Can detect numbers that overflow", func(t *testing.T) {
+		p := []byte{0xd6, 0xc3, 0xc4, 0, 0, vcdSource,
+			0x8f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f, // source size
+			0x80, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f, // source position
+		}
+		got, err := applyVCDIFF(p, source)
+		if err.Error() != "invalid patch" {
+			t.Errorf("applyVCDIFF() = %v, want %v", err, "invalid patch")
+		}
+		if got != nil {
+			t.Errorf("applyVCDIFF() = %v, want %v", got, nil)
+		}
+	})
+
+	t.Run("Can detect a target too big", func(t *testing.T) {
+		p := []byte{0xd6, 0xc3, 0xc4, 0, 0, 0,
+			0,                            // delta length
+			0x87, 0xff, 0xff, 0xff, 0x7f, // window size
+		}
+		got, err := applyVCDIFF(p, source)
+		if err.Error() != "target too big" {
+			t.Errorf("applyVCDIFF() = %v, want %v", err, "target too big")
+		}
+		if got != nil {
+			t.Errorf("applyVCDIFF() = %v, want %v", got, nil)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package recording
+
+import (
+	"bufio"
+	"image"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// encoderName is the external encoder used when it is installed
+var encoderName = "ffmpeg"
+
+// files writes the video and the audio uncompressed, as a Y4M and a WAV file
+type files struct {
+	fd    *os.File
+	buf   *bufio.Writer
+	video *y4mWriter
+	audio *WAV
+}
+
+func createFiles(base string, fps float64, rate uint32) (*files, error) {
+	fd, err := os.Create(base + ".y4m")
+	if err != nil {
+		return nil, err
+	}
+	audio, err := CreateWAV(base+".wav", rate)
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	buf := bufio.NewWriter(fd)
+	return &files{fd, buf, &y4mWriter{w: buf, fps: fps}, audio}, nil
+}
+
+func (f *files) frame(img *image.RGBA) error {
+	return f.video.frame(img)
+}
+
+func (f *files) samples(buf []byte) error {
+	_, err := f.audio.Write(buf)
+	return err
+}
+
+func (f *files) close() error {
+	err := f.buf.Flush()
+	if cerr := f.fd.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := f.audio.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// encoder pipes the video to the standard input of the encoder as a Y4M
+// stream, and the raw audio to its file descriptor 3
+type encoder struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	video *y4mWriter
+	audio *os.File
+}
+
+func startEncoder(bin, path string, fps float64, rate uint32) (*encoder, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	cmd := exec.Command(bin, "-loglevel", "error", "-y",
+		"-f", "yuv4mpegpipe", "-i", "pipe:0",
+		"-f", "s16le", "-ar", strconv.Itoa(int(rate)), "-ac", "2", "-i", "pipe:3",
+		"-vf", "pad=ceil(iw/2)*2:ceil(ih/2)*2", "-pix_fmt", "yuv420p",
+		path)
+	cmd.ExtraFiles = []*os.File{r}
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		w.Close()
+		return nil, err
+	}
+	return &encoder{cmd, stdin, &y4mWriter{w: stdin, fps: fps}, w}, nil
+}
+
+func (e *encoder) frame(img *image.RGBA) error {
+	return e.video.frame(img)
+}
+
+func (e *encoder) samples(buf []byte) error {
+	_, err := e.audio.Write(buf)
+	return err
+}
+
+// close ends the streams and waits for the encoder to finish the file
+func (e *encoder) close() error {
+	e.stdin.Close()
+	e.audio.Close()
+	return e.cmd.Wait()
+}
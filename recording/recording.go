@@ -0,0 +1,102 @@
+// Package recording records gameplay clips. It receives the frames of the
+// video package and the samples of the audio package, and writes them with
+// the timing of the core. The clips are encoded by ffmpeg when it is
+// installed, or written uncompressed as a Y4M and a WAV file.
+package recording
+
+import (
+	"errors"
+	"image"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/libretro/ludo/libretro"
+	ntf "github.com/libretro/ludo/notifications"
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/utils"
+)
+
+// sink receives the frames and the samples of a recording
+type sink interface {
+	frame(img *image.RGBA) error
+	samples(buf []byte) error
+	close() error
+}
+
+var current sink
+
+// Active returns true if a clip is being recorded
+func Active() bool {
+	return current != nil
+}
+
+// Start starts recording the running game in the recordings directory, with
+// the frame rate and the sample rate of timing. It returns the path of the
+// clip.
+func Start(timing libretro.SystemTiming) (string, error) {
+	if current != nil {
+		return "", errors.New("already recording")
+	}
+
+	dir := settings.Current.RecordingsDirectory
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+	base := filepath.Join(dir, utils.DatedName(state.ContentPath()))
+	rate := uint32(math.Round(timing.SampleRate))
+
+	if bin, err := exec.LookPath(encoderName); encoderName != "" && err == nil {
+		e, err := startEncoder(bin, base+".mkv", timing.FPS, rate)
+		if err == nil {
+			current = e
+			return base + ".mkv", nil
+		}
+		ntf.DisplayAndLog(ntf.Warning, "Recording", "Could not start %s: %s", encoderName, err)
+	}
+
+	f, err := createFiles(base, timing.FPS, rate)
+	if err != nil {
+		return "", err
+	}
+	current = f
+	return base + ".y4m", nil
+}
+
+// Stop ends the recording
+func Stop() error {
+	if current == nil {
+		return nil
+	}
+	err := current.close()
+	current = nil
+	return err
+}
+
+// fail stops a recording that can't be written anymore
+func fail(err error) {
+	Stop()
+	ntf.DisplayAndLog(ntf.Error, "Recording", "Recording stopped: %s", err)
+}
+
+// Frame records a frame of the game. A nil frame repeats the previous one.
+func Frame(img *image.RGBA) {
+	if current == nil {
+		return
+	}
+	if err := current.frame(img); err != nil {
+		fail(err)
+	}
+}
+
+// Samples records interleaved stereo 16-bit samples, at the rate of the core
+func Samples(buf []byte) {
+	if current == nil {
+		return
+	}
+	if err := current.samples(buf); err != nil {
+		fail(err)
+	}
+}
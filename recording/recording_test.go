@@ -0,0 +1,138 @@
+package recording
+
+import (
+	"bytes"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/state"
+	wav "github.com/youpy/go-wav"
+)
+
+// solid returns a frame filled with one color
+func solid(w, h int, r, g, b byte) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = r, g, b, 0xff
+	}
+	return img
+}
+
+func Test_y4mWriter(t *testing.T) {
+	var buf bytes.Buffer
+	y := &y4mWriter{w: &buf, fps: 60.0988}
+
+	t.Run("Waits for the first frame", func(t *testing.T) {
+		if err := y.frame(nil); err != nil || buf.Len() != 0 {
+			t.Errorf("got = %v, want nothing written", buf.Len())
+		}
+	})
+
+	t.Run("Writes the header and the planes", func(t *testing.T) {
+		y.frame(solid(2, 1, 0xff, 0xff, 0xff))
+		want := "YUV4MPEG2 W2 H1 F60099:1000 Ip A1:1 C444 XCOLORRANGE=FULL\nFRAME\n" +
+			"\xff\xff\x80\x80\x80\x80"
+		if got := buf.String(); got != want {
+			t.Errorf("got = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Repeats duped frames", func(t *testing.T) {
+		buf.Reset()
+		y.frame(nil)
+		if got := buf.String(); got != "FRAME\n\xff\xff\x80\x80\x80\x80" {
+			t.Errorf("got = %q, want a copy of the previous frame", got)
+		}
+	})
+
+	t.Run("Scales frames to the size of the stream", func(t *testing.T) {
+		buf.Reset()
+		y.frame(solid(4, 2, 0, 0, 0))
+		if got := buf.Len(); got != len("FRAME\n")+2*1*3 {
+			t.Errorf("got = %v, want %v", got, len("FRAME\n")+2*1*3)
+		}
+	})
+}
+
+func TestStart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ludo-recording")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	settings.Current.RecordingsDirectory = dir
+	state.GamePath = "roms/Game.sfc"
+	defer func() { state.GamePath = "" }()
+	encoderName = ""
+
+	path, err := Start(libretro.SystemTiming{FPS: 60, SampleRate: 32040.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Active() {
+		t.Errorf("got = %v, want %v", Active(), true)
+	}
+	if _, err := Start(libretro.SystemTiming{FPS: 60, SampleRate: 32040}); err == nil {
+		t.Errorf("got = %v, want an error", err)
+	}
+
+	Frame(solid(4, 4, 0x10, 0x20, 0x30))
+	Samples([]byte{1, 0, 2, 0, 3, 0, 4, 0})
+	Frame(nil)
+	if err := Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Writes the video", func(t *testing.T) {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(b), "YUV4MPEG2 W4 H4 F60000:1000") {
+			t.Errorf("got = %q, want a Y4M header", b[:30])
+		}
+		if got := strings.Count(string(b), "FRAME\n"); got != 2 {
+			t.Errorf("got = %v, want %v", got, 2)
+		}
+	})
+
+	t.Run("Writes the audio", func(t *testing.T) {
+		f, err := os.Open(strings.TrimSuffix(path, ".y4m") + ".wav")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		r := wav.NewReader(f)
+		format, err := r.Format()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if format.SampleRate != 32041 {
+			t.Errorf("got = %v, want %v", format.SampleRate, 32041)
+		}
+		samples, _ := r.ReadSamples()
+		if len(samples) != 2 {
+			t.Errorf("got = %v, want %v", len(samples), 2)
+		}
+	})
+
+	t.Run("Names the files after the game", func(t *testing.T) {
+		if !strings.HasPrefix(filepath.Base(path), "Game@") {
+			t.Errorf("got = %v, want %v", filepath.Base(path), "Game@...")
+		}
+	})
+
+	t.Run("Ignores frames when stopped", func(t *testing.T) {
+		Frame(nil)
+		Samples([]byte{1, 0, 2, 0})
+		if Active() {
+			t.Errorf("got = %v, want %v", Active(), false)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package recording
+
+import (
+	"encoding/binary"
+	"os"
+)
+
+// WAV writes stereo 16-bit samples to a WAV file. The sizes in the header are
+// written when the file is closed.
+type WAV struct {
+	f    *os.File
+	rate uint32
+	size uint32
+}
+
+// wavHeader returns the header of a WAV file of stereo 16-bit samples
+func wavHeader(rate, dataSize uint32) []byte {
+	const channels, bits = 2, 16
+	h := make([]byte, 44)
+	copy(h[0:], "RIFF")
+	binary.LittleEndian.PutUint32(h[4:], 36+dataSize)
+	copy(h[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(h[16:], 16) // size of the fmt chunk
+	binary.LittleEndian.PutUint16(h[20:], 1)  // PCM
+	binary.LittleEndian.PutUint16(h[22:], channels)
+	binary.LittleEndian.PutUint32(h[24:], rate)
+	binary.LittleEndian.PutUint32(h[28:], rate*channels*bits/8)
+	binary.LittleEndian.PutUint16(h[32:], channels*bits/8)
+	binary.LittleEndian.PutUint16(h[34:], bits)
+	copy(h[36:], "data")
+	binary.LittleEndian.PutUint32(h[40:], dataSize)
+	return h
+}
+
+// CreateWAV creates a WAV file of samples at the given rate
+func CreateWAV(path string, rate uint32) (*WAV, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(wavHeader(rate, 0)); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &WAV{f: f, rate: rate}, nil
+}
+
+// Write appends interleaved samples to the file
+func (w *WAV) Write(p []byte) (int, error) {
+	n, err := w.f.Write(p)
+	w.size += uint32(n)
+	return n, err
+}
+
+// Close writes the final header and closes the file
+func (w *WAV) Close() error {
+	if _, err := w.f.WriteAt(wavHeader(w.rate, w.size), 0); err != nil {
+		w.f.Close()
+		return err
+	}
+	return w.f.Close()
+}
@@ -0,0 +1,72 @@
+package recording
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// y4mWriter writes frames as a YUV4MPEG2 stream. The chroma is not subsampled
+// so the colors of the games are kept. The size of the stream is the size of
+// the first frame, the next frames are scaled to it.
+type y4mWriter struct {
+	w             io.Writer
+	fps           float64
+	width, height int
+	last          *image.RGBA
+	buf           []byte
+}
+
+// header returns the stream header. The frame rate is written as a fraction
+// of 1000 as the cores rarely run at an integer rate.
+func (y *y4mWriter) header() string {
+	return fmt.Sprintf("YUV4MPEG2 W%d H%d F%d:1000 Ip A1:1 C444 XCOLORRANGE=FULL\n",
+		y.width, y.height, int(math.Round(y.fps*1000)))
+}
+
+// frame writes a frame. A nil frame repeats the previous one, like the cores
+// do when they dupe frames.
+func (y *y4mWriter) frame(img *image.RGBA) error {
+	if img == nil {
+		img = y.last
+	}
+	if img == nil {
+		return nil
+	}
+
+	if y.width == 0 {
+		y.width, y.height = img.Rect.Dx(), img.Rect.Dy()
+		y.buf = make([]byte, y.width*y.height*3)
+		if _, err := io.WriteString(y.w, y.header()); err != nil {
+			return err
+		}
+	}
+
+	if img.Rect.Dx() != y.width || img.Rect.Dy() != y.height {
+		n := imaging.Resize(img, y.width, y.height, imaging.NearestNeighbor)
+		img = &image.RGBA{Pix: n.Pix, Stride: n.Stride, Rect: n.Rect}
+	}
+	y.last = img
+
+	plane := y.width * y.height
+	for j := 0; j < y.height; j++ {
+		for i := 0; i < y.width; i++ {
+			p := img.PixOffset(img.Rect.Min.X+i, img.Rect.Min.Y+j)
+			l, cb, cr := color.RGBToYCbCr(img.Pix[p], img.Pix[p+1], img.Pix[p+2])
+			o := j*y.width + i
+			y.buf[o] = l
+			y.buf[plane+o] = cb
+			y.buf[2*plane+o] = cr
+		}
+	}
+
+	if _, err := io.WriteString(y.w, "FRAME\n"); err != nil {
+		return err
+	}
+	_, err := y.w.Write(y.buf)
+	return err
+}
@@ -0,0 +1,238 @@
+// Package rewind allows stepping the game backwards in real time. It keeps a
+// ring buffer of savestates captured every few frames. To keep the memory
+// usage bounded, only the difference between two consecutive states is stored,
+// in a compressed form.
+package rewind
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/state"
+)
+
+// Buffer is a ring buffer of compressed deltas between consecutive states.
+// Only the most recent state is kept in full, older states are rebuilt by
+// applying the deltas backwards.
+type Buffer struct {
+	deltas [][]byte // compressed deltas, from the oldest to the most recent
+	last   []byte   // the most recent state, uncompressed
+	size   int      // the memory used by the deltas, in bytes
+	budget int      // the maximum memory the deltas can use, in bytes
+}
+
+// NewBuffer creates a Buffer that will keep at most budget bytes of deltas
+func NewBuffer(budget int) *Buffer {
+	return &Buffer{budget: budget}
+}
+
+// Push stores a new state in the buffer. Oldest deltas are discarded if the
+// memory budget is exceeded.
+func (b *Buffer) Push(s []byte) {
+	if b.last != nil && len(b.last) != len(s) {
+		// The state size changed, old deltas can't be applied anymore
+		b.Reset()
+	}
+
+	if b.last != nil {
+		d := encodeDelta(b.last, s)
+		b.deltas = append(b.deltas, d)
+		b.size += len(d)
+	}
+
+	for b.size > b.budget && len(b.deltas) > 0 {
+		b.size -= len(b.deltas[0])
+		b.deltas[0] = nil
+		b.deltas = b.deltas[1:]
+	}
+
+	b.last = append(b.last[:0], s...)
+}
+
+// Pop rebuilds the previous state and removes the most recent one from the
+// buffer. It returns false if there is nothing left to rewind.
+func (b *Buffer) Pop() ([]byte, bool) {
+	if len(b.deltas) == 0 {
+		return nil, false
+	}
+
+	i := len(b.deltas) - 1
+	d := b.deltas[i]
+	b.deltas[i] = nil
+	b.deltas = b.deltas[:i]
+	b.size -= len(d)
+
+	if err := decodeDelta(b.last, d); err != nil {
+		b.Reset()
+		return nil, false
+	}
+
+	s := make([]byte, len(b.last))
+	copy(s, b.last)
+	return s, true
+}
+
+// Len returns the number of states that can be rewound
+func (b *Buffer) Len() int {
+	return len(b.deltas)
+}
+
+// Size returns the memory used by the deltas, in bytes
+func (b *Buffer) Size() int {
+	return b.size
+}
+
+// Reset empties the buffer
+func (b *Buffer) Reset() {
+	b.deltas = nil
+	b.last = nil
+	b.size = 0
+}
+
+// encodeDelta XORs prev and next and compresses the result. As consecutive
+// states are very similar, the XOR is mostly made of zeros. Runs of zeros are
+// stored as a length, the rest is stored as is:
+// [zero run length][literal length][literal bytes]...
+// Both lengths are encoded as uvarints.
+func encodeDelta(prev, next []byte) []byte {
+	out := []byte{}
+	i := 0
+	for i < len(next) {
+		zeros := 0
+		for i < len(next) && prev[i] == next[i] {
+			zeros++
+			i++
+		}
+
+		start := i
+		for i < len(next) && prev[i] != next[i] {
+			i++
+		}
+
+		out = appendUvarint(out, uint64(zeros))
+		out = appendUvarint(out, uint64(i-start))
+		for j := start; j < i; j++ {
+			out = append(out, prev[j]^next[j])
+		}
+	}
+	return out
+}
+
+// decodeDelta applies a delta produced by encodeDelta to s, in place.
+// The XOR being symmetric, the same delta allows going in both directions.
+func decodeDelta(s, delta []byte) error {
+	i := 0
+	offset := 0
+	for offset < len(delta) {
+		zeros, n := binary.Uvarint(delta[offset:])
+		if n <= 0 {
+			return errors.New("invalid delta")
+		}
+		offset += n
+
+		length, n := binary.Uvarint(delta[offset:])
+		if n <= 0 {
+			return errors.New("invalid delta")
+		}
+		offset += n
+
+		i += int(zeros)
+		if i+int(length) > len(s) || offset+int(length) > len(delta) {
+			return errors.New("invalid delta")
+		}
+
+		for j := 0; j < int(length); j++ {
+			s[i] ^= delta[offset]
+			i++
+			offset++
+		}
+	}
+	return nil
+}
+
+func appendUvarint(buf []byte, x uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], x)
+	return append(buf, tmp[:n]...)
+}
+
+var buffer *Buffer
+var frame int
+var popFrame int
+
+// ErrEmpty is returned by Pop when there is nothing left to rewind
+var ErrEmpty = errors.New("nothing to rewind")
+
+// Reset discards the rewind history. It should be called when a game is
+// loaded or unloaded.
+func Reset() {
+	buffer = nil
+	frame = 0
+	popFrame = 0
+}
+
+// interval returns the number of frames between two states of the buffer
+func interval() int {
+	if settings.Current.RewindInterval < 1 {
+		return 1
+	}
+	return settings.Current.RewindInterval
+}
+
+// Push captures the current state of the core every RewindInterval frames.
+// It is meant to be called for each frame.
+func Push() error {
+	if !settings.Current.RewindEnabled || !state.CoreRunning {
+		return nil
+	}
+
+	popFrame = 0
+	frame++
+	if frame%interval() != 0 {
+		return nil
+	}
+
+	budget := settings.Current.RewindBufferSize * 1024 * 1024
+	if buffer == nil || buffer.budget != budget {
+		buffer = NewBuffer(budget)
+	}
+
+	s := state.Core.SerializeSize()
+	if s == 0 {
+		return errors.New("core doesn't support serialization")
+	}
+	bytes, err := state.Core.Serialize(s)
+	if err != nil {
+		return err
+	}
+	buffer.Push(bytes)
+	return nil
+}
+
+// Pop is meant to be called for each frame while the rewind hotkey is held.
+// It restores the previous state captured by Push every RewindInterval frames,
+// and holds the restored state in between, so the game goes backwards at the
+// speed it was played. When the buffer is empty, the oldest state is held and
+// ErrEmpty is returned.
+func Pop() error {
+	if !settings.Current.RewindEnabled || !state.CoreRunning || buffer == nil {
+		return nil
+	}
+
+	var err error
+	if popFrame%interval() == 0 {
+		if _, ok := buffer.Pop(); !ok {
+			err = ErrEmpty
+		}
+	}
+	popFrame++
+
+	if buffer.last == nil {
+		return err
+	}
+	if uerr := state.Core.Unserialize(buffer.last, uint(len(buffer.last))); uerr != nil {
+		return uerr
+	}
+	return err
+}
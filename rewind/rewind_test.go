@@ -0,0 +1,87 @@
+package rewind
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_encodeDelta(t *testing.T) {
+	t.Run("Can restore the previous state", func(t *testing.T) {
+		prev := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		next := []byte{0, 1, 9, 3, 4, 5, 6, 7, 1, 1}
+		d := encodeDelta(prev, next)
+		got := append([]byte{}, next...)
+		err := decodeDelta(got, d)
+		if err != nil {
+			t.Errorf("decodeDelta() = %v, want %v", err, nil)
+		}
+		if !reflect.DeepEqual(got, prev) {
+			t.Errorf("got = %v, want %v", got, prev)
+		}
+	})
+
+	t.Run("Compresses identical states", func(t *testing.T) {
+		s := make([]byte, 4096)
+		d := encodeDelta(s, s)
+		if len(d) > 4 {
+			t.Errorf("len(d) = %v, want <= %v", len(d), 4)
+		}
+	})
+
+	t.Run("Detects a corrupted delta", func(t *testing.T) {
+		s := make([]byte, 4)
+		err := decodeDelta(s, []byte{2, 8, 1, 1})
+		if err == nil {
+			t.Errorf("decodeDelta() = %v, want an error", err)
+		}
+	})
+}
+
+func Test_Buffer(t *testing.T) {
+	t.Run("Pops states in reverse order", func(t *testing.T) {
+		b := NewBuffer(1024)
+		b.Push([]byte{1, 1, 1, 1})
+		b.Push([]byte{1, 2, 1, 1})
+		b.Push([]byte{1, 2, 3, 1})
+
+		got, ok := b.Pop()
+		if !ok || !reflect.DeepEqual(got, []byte{1, 2, 1, 1}) {
+			t.Errorf("Pop() = %v, want %v", got, []byte{1, 2, 1, 1})
+		}
+		got, ok = b.Pop()
+		if !ok || !reflect.DeepEqual(got, []byte{1, 1, 1, 1}) {
+			t.Errorf("Pop() = %v, want %v", got, []byte{1, 1, 1, 1})
+		}
+		_, ok = b.Pop()
+		if ok {
+			t.Errorf("Pop() = %v, want %v", ok, false)
+		}
+	})
+
+	t.Run("Discards the oldest states when the budget is exceeded", func(t *testing.T) {
+		b := NewBuffer(10)
+		for i := 0; i < 20; i++ {
+			b.Push([]byte{byte(i), 0, 0, 0})
+		}
+		if b.Size() > 10 {
+			t.Errorf("Size() = %v, want <= %v", b.Size(), 10)
+		}
+		if b.Len() == 0 || b.Len() >= 19 {
+			t.Errorf("Len() = %v, want between 1 and 18", b.Len())
+		}
+		got, _ := b.Pop()
+		if !reflect.DeepEqual(got, []byte{18, 0, 0, 0}) {
+			t.Errorf("Pop() = %v, want %v", got, []byte{18, 0, 0, 0})
+		}
+	})
+
+	t.Run("Resets when the state size changes", func(t *testing.T) {
+		b := NewBuffer(1024)
+		b.Push([]byte{1, 1})
+		b.Push([]byte{1, 2})
+		b.Push([]byte{1, 2, 3})
+		if b.Len() != 0 {
+			t.Errorf("Len() = %v, want %v", b.Len(), 0)
+		}
+	})
+}
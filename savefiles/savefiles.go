@@ -18,11 +18,62 @@ import (
 
 var mutex sync.Mutex
 
+// slot is a persistent memory of the game and the file it is saved to
+type slot struct {
+	memType uint32
+	path    string
+}
+
+// slots of the running subsystem game, if any
+var slots []slot
+
 // path returns the path of the SRAM file for the current core
 func path() string {
 	return filepath.Join(
 		settings.Current.SavefilesDirectory,
-		utils.FileName(state.GamePath)+".srm")
+		utils.FileName(state.ContentPath())+".srm")
+}
+
+// SetSubsystem makes SaveSRAM and LoadSRAM handle the memories of each rom of
+// a subsystem game, instead of the SRAM of a regular game. The paths are in
+// the order of the roms of the subsystem. Passing nil restores the default.
+func SetSubsystem(si *libretro.SubsystemInfo, paths []string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	slots = subsystemSlots(si, paths)
+}
+
+// subsystemSlots lists the memories of the roms of a subsystem game. Each
+// memory is saved to a file named after its rom, with the extension
+// requested by the core.
+func subsystemSlots(si *libretro.SubsystemInfo, paths []string) []slot {
+	if si == nil {
+		return nil
+	}
+	s := []slot{}
+	for i, rom := range si.Roms {
+		if i >= len(paths) || paths[i] == "" {
+			continue
+		}
+		for _, mem := range rom.Memory {
+			s = append(s, slot{
+				memType: mem.Type,
+				path: filepath.Join(
+					settings.Current.SavefilesDirectory,
+					utils.FileName(paths[i])+"."+mem.Extension),
+			})
+		}
+	}
+	return s
+}
+
+// currentSlots returns the memories to save for the running game
+func currentSlots() []slot {
+	if slots != nil {
+		return slots
+	}
+	return []slot{{libretro.MemorySaveRAM, path()}}
 }
 
 // SaveSRAM saves the game SRAM to the filesystem
@@ -34,8 +85,19 @@ func SaveSRAM() error {
 		return errors.New("core not running")
 	}
 
-	len := state.Core.GetMemorySize(libretro.MemorySaveRAM)
-	ptr := state.Core.GetMemoryData(libretro.MemorySaveRAM)
+	var err error
+	for _, s := range currentSlots() {
+		if e := saveMemory(s); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// saveMemory writes a memory of the core to its file
+func saveMemory(s slot) error {
+	len := state.Core.GetMemorySize(s.memType)
+	ptr := state.Core.GetMemoryData(s.memType)
 	if ptr == nil || len == 0 {
 		return errors.New("unable to get SRAM address")
 	}
@@ -47,7 +109,7 @@ func SaveSRAM() error {
 		return err
 	}
 
-	fd, err := os.Create(path())
+	fd, err := os.Create(s.path)
 	if err != nil {
 		return err
 	}
@@ -75,8 +137,19 @@ func LoadSRAM() error {
 		return errors.New("core not running")
 	}
 
-	len := state.Core.GetMemorySize(libretro.MemorySaveRAM)
-	ptr := state.Core.GetMemoryData(libretro.MemorySaveRAM)
+	var err error
+	for _, s := range currentSlots() {
+		if e := loadMemory(s); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// loadMemory reads a memory of the core from its file
+func loadMemory(s slot) error {
+	len := state.Core.GetMemorySize(s.memType)
+	ptr := state.Core.GetMemoryData(s.memType)
 	if ptr == nil || len == 0 {
 		return errors.New("unable to get SRAM address")
 	}
@@ -84,7 +157,7 @@ func LoadSRAM() error {
 	// this *[1 << 30]byte points to the same memory as ptr, allowing to
 	// overwrite this memory
 	destination := (*[1 << 30]byte)(unsafe.Pointer(ptr))[:len:len]
-	source, err := ioutil.ReadFile(path())
+	source, err := ioutil.ReadFile(s.path)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,69 @@
+package savestates
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/libretro/ludo/libretro"
+)
+
+// Meta describes a savestate. It is stored in a JSON sidecar file next to the
+// state, states without sidecar can still be loaded.
+type Meta struct {
+	CoreName    string    `json:"core_name"`
+	CoreVersion string    `json:"core_version"`
+	GameCRC     uint32    `json:"game_crc"`
+	Playtime    int64     `json:"playtime"` // seconds played when the state was saved
+	Thumbnail   string    `json:"thumbnail,omitempty"`
+	Label       string    `json:"label,omitempty"`
+	Date        time.Time `json:"date"`
+}
+
+// metaPath returns the path of the sidecar file of a savestate
+func metaPath(statePath string) string {
+	return statePath + ".json"
+}
+
+// ReadMeta reads the sidecar file of a savestate. It returns nil if the state
+// has no sidecar file.
+func ReadMeta(statePath string) (*Meta, error) {
+	b, err := ioutil.ReadFile(metaPath(statePath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Meta
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// writeMeta writes the sidecar file of a savestate
+func writeMeta(statePath string, m Meta) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(metaPath(statePath), b, 0644)
+}
+
+// check returns an error if a savestate can't be loaded by the running core
+// and game. An unknown game CRC is not checked.
+func check(m *Meta, si libretro.SystemInfo, crc uint32) error {
+	if m.CoreName != si.LibraryName {
+		return fmt.Errorf("this state was saved with %s, not %s", m.CoreName, si.LibraryName)
+	}
+	if m.CoreVersion != si.LibraryVersion {
+		return fmt.Errorf("this state was saved with %s %s, not %s", m.CoreName, m.CoreVersion, si.LibraryVersion)
+	}
+	if m.GameCRC != 0 && crc != 0 && m.GameCRC != crc {
+		return fmt.Errorf("this state was saved with another version of the game")
+	}
+	return nil
+}
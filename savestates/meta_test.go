@@ -0,0 +1,151 @@
+package savestates
+
+import (
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/libretro/ludo/libretro"
+)
+
+func Test_check(t *testing.T) {
+	si := libretro.SystemInfo{LibraryName: "VecX", LibraryVersion: "1.2"}
+	tests := []struct {
+		name string
+		meta Meta
+		crc  uint32
+		want string
+	}{
+		{
+			name: "Same core and game",
+			meta: Meta{CoreName: "VecX", CoreVersion: "1.2", GameCRC: 42},
+			crc:  42,
+			want: "",
+		},
+		{
+			name: "Unknown game CRC",
+			meta: Meta{CoreName: "VecX", CoreVersion: "1.2"},
+			crc:  42,
+			want: "",
+		},
+		{
+			name: "Other core",
+			meta: Meta{CoreName: "Genesis Plus GX", CoreVersion: "1.7.4"},
+			crc:  42,
+			want: "this state was saved with Genesis Plus GX, not VecX",
+		},
+		{
+			name: "Other version",
+			meta: Meta{CoreName: "VecX", CoreVersion: "1.1"},
+			crc:  42,
+			want: "this state was saved with VecX 1.1, not 1.2",
+		},
+		{
+			name: "Other game",
+			meta: Meta{CoreName: "VecX", CoreVersion: "1.2", GameCRC: 41},
+			crc:  42,
+			want: "this state was saved with another version of the game",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if err := check(&tt.meta, si, tt.crc); err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMeta(t *testing.T) {
+	dir, err := ioutil.TempDir("", "savestates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	t.Run("No sidecar", func(t *testing.T) {
+		got, err := ReadMeta(filepath.Join(dir, "old.state"))
+		if got != nil || err != nil {
+			t.Errorf("got = %v, %v, want nil, nil", got, err)
+		}
+	})
+
+	t.Run("Reads what was written", func(t *testing.T) {
+		path := filepath.Join(dir, "game@slot1.state")
+		want := Meta{
+			CoreName:    "VecX",
+			CoreVersion: "1.2",
+			GameCRC:     0xdeadbeef,
+			Playtime:    3723,
+			Label:       "Before the boss",
+			Date:        time.Date(2020, 5, 17, 20, 30, 0, 0, time.UTC),
+		}
+		if err := writeMeta(path, want); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadMeta(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*got, want) {
+			t.Errorf("got = %v, want %v", *got, want)
+		}
+	})
+}
+
+func TestSetSlot(t *testing.T) {
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{n: 3, want: 3},
+		{n: Slots, want: 0},
+		{n: -1, want: Slots - 1},
+	}
+	for _, tt := range tests {
+		SetSlot(tt.n)
+		if got := Slot(); got != tt.want {
+			t.Errorf("got = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestSetGamePath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ludo-savestates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "game.sfc")
+	if err := ioutil.WriteFile(path, []byte("game"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	SetGamePath(path)
+	want := crc32.ChecksumIEEE([]byte("game"))
+	for i := 0; i < 100 && gameCRC() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := gameCRC(); got != want {
+		t.Errorf("got = %v, want %v", got, want)
+	}
+
+	Reset()
+	if got := gameCRC(); got != 0 {
+		t.Errorf("got = %v, want %v", got, 0)
+	}
+
+	SetGameData([]byte("patched"))
+	if got := gameCRC(); got != crc32.ChecksumIEEE([]byte("patched")) {
+		t.Errorf("got = %v, want %v", got, crc32.ChecksumIEEE([]byte("patched")))
+	}
+	Reset()
+}
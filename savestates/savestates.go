@@ -3,37 +3,222 @@
 package savestates
 
 import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
+	"github.com/libretro/ludo/libretro"
 	"github.com/libretro/ludo/settings"
 	"github.com/libretro/ludo/state"
+	"github.com/libretro/ludo/utils"
+	"github.com/libretro/ludo/vfs"
 )
 
+// Slots is the number of quick save slots
+const Slots = 10
+
+var (
+	slot     int           // current quick save slot
+	playtime time.Duration // time spent playing the current game
+	crcMu    sync.Mutex
+	crc      uint32 // CRC32 of the current game, 0 until it is known
+	crcGen   int    // incremented for each game, to drop outdated CRCs
+)
+
+// Frame counts the playtime. It has to be called after each frame run by the
+// core, with the duration of the frame in seconds.
+func Frame(dt float32) {
+	playtime += time.Duration(dt * float32(time.Second))
+}
+
+// Playtime returns the time spent playing the current game
+func Playtime() time.Duration {
+	return playtime
+}
+
+// Reset forgets the playtime of the current game
+func Reset() {
+	playtime = 0
+	crcMu.Lock()
+	crc = 0
+	crcGen++
+	crcMu.Unlock()
+}
+
+// SetGameData computes the CRC32 of the game from the data given to the core
+func SetGameData(data []byte) {
+	crcMu.Lock()
+	defer crcMu.Unlock()
+	crc = crc32.ChecksumIEEE(data)
+	crcGen++
+}
+
+// SetGamePath computes the CRC32 of the game from the path given to the core,
+// which can be a file inside an archive. Games like CD images can be large, so
+// the file is read in the background. Until then, the game of the savestates
+// is not checked.
+func SetGamePath(path string) {
+	crcMu.Lock()
+	crc = 0
+	crcGen++
+	gen := crcGen
+	crcMu.Unlock()
+
+	go func() {
+		sum, err := fileCRC(path)
+		if err != nil {
+			return
+		}
+		crcMu.Lock()
+		defer crcMu.Unlock()
+		if gen == crcGen {
+			crc = sum
+		}
+	}()
+}
+
+// fileCRC returns the CRC32 of a file, read through the VFS
+func fileCRC(path string) (uint32, error) {
+	f, err := vfs.FS{}.Open(path, libretro.VFSFileAccessRead)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	h := crc32.NewIEEE()
+	if _, err := io.Copy(h, f); err != nil {
+		return 0, err
+	}
+	return h.Sum32(), nil
+}
+
+// gameCRC returns the CRC32 of the current game, or 0 if it is not known
+func gameCRC() uint32 {
+	crcMu.Lock()
+	defer crcMu.Unlock()
+	return crc
+}
+
+// Path returns the path of the savestate with the given name
+func Path(name string) string {
+	return filepath.Join(settings.Current.SavestatesDirectory, name+".state")
+}
+
 // Save the current state to the filesystem. name is the name of the
 // savestate file to save to, without extension.
 func Save(name string) error {
+	return SaveWithLabel(name, "")
+}
+
+// SaveWithLabel saves the current state to the filesystem, along with a
+// sidecar file describing it. label is an optional user label.
+func SaveWithLabel(name, label string) error {
 	s := state.Core.SerializeSize()
 	bytes, err := state.Core.Serialize(s)
 	if err != nil {
 		return err
 	}
-	path := filepath.Join(settings.Current.SavestatesDirectory, name+".state")
+	path := Path(name)
 	err = os.MkdirAll(settings.Current.SavestatesDirectory, os.ModePerm)
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(path, bytes, 0644)
+	err = ioutil.WriteFile(path, bytes, 0644)
+	if err != nil {
+		return err
+	}
+
+	si := state.Core.GetSystemInfo()
+	m := Meta{
+		CoreName:    si.LibraryName,
+		CoreVersion: si.LibraryVersion,
+		GameCRC:     gameCRC(),
+		Playtime:    int64(playtime / time.Second),
+		Label:       label,
+		Date:        time.Now(),
+	}
+	thumbnail := filepath.Join(settings.Current.ScreenshotsDirectory, name+".png")
+	if _, err := os.Stat(thumbnail); err == nil {
+		m.Thumbnail = thumbnail
+	}
+	return writeMeta(path, m)
 }
 
-// Load the state from the filesystem
+// Load the state from the filesystem. States saved by another core or
+// another version of the core are refused.
 func Load(path string) error {
+	m, err := ReadMeta(path)
+	if err != nil {
+		return err
+	}
+	if m != nil {
+		if err := check(m, state.Core.GetSystemInfo(), gameCRC()); err != nil {
+			return err
+		}
+	}
+
 	s := state.Core.SerializeSize()
 	bytes, err := ioutil.ReadFile(path)
 	if err != nil {
 		return err
 	}
+	if uint(len(bytes)) < s {
+		return fmt.Errorf("this state is too small for this core: %d bytes, %d expected", len(bytes), s)
+	}
 	err = state.Core.Unserialize(bytes, s)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if m != nil {
+		playtime = time.Duration(m.Playtime) * time.Second
+	}
+	return nil
+}
+
+// Delete removes a savestate and its sidecar file
+func Delete(path string) error {
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	if err := os.Remove(metaPath(path)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Slot returns the current quick save slot
+func Slot() int {
+	return slot
+}
+
+// SetSlot changes the current quick save slot, wrapping around
+func SetSlot(n int) {
+	slot = (n%Slots + Slots) % Slots
+}
+
+// SlotName returns the name of the savestate of a quick save slot for the
+// current game, without extension
+func SlotName(n int) string {
+	return fmt.Sprintf("%s@slot%d", utils.FileName(state.ContentPath()), n)
+}
+
+// AutoName returns the name of the savestate saved automatically when the
+// current game is unloaded, without extension
+func AutoName() string {
+	return utils.FileName(state.ContentPath()) + "@auto"
+}
+
+// LoadSlot loads the state of the current quick save slot
+func LoadSlot() error {
+	path := Path(SlotName(slot))
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return errors.New("this slot is empty")
+	}
+	return Load(path)
 }
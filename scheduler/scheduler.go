@@ -0,0 +1,119 @@
+// Package scheduler paces the frames of the core to the frame rate of the
+// emulated system, whatever the refresh rate of the display is.
+package scheduler
+
+import (
+	"math"
+	"time"
+
+	"github.com/libretro/ludo/audio"
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/state"
+)
+
+// The sync modes
+const (
+	// VSync waits for the display refresh, and runs as many core frames as
+	// needed to keep the speed of the game
+	VSync = "VSync"
+	// AudioSync lets the audio backend pace the frames by blocking
+	AudioSync = "Audio Sync"
+	// Free sleeps between the frames, it suits variable refresh rate displays
+	Free = "Free"
+)
+
+// Modes lists the sync modes, the first one is the default
+var Modes = []string{VSync, AudioSync, Free}
+
+// lockThreshold is how close the frame rate of the core has to be to the
+// refresh rate to run one frame per refresh. The audio resampler absorbs the
+// difference.
+const lockThreshold = 0.01
+
+var (
+	fps  = 60.0
+	next time.Time // deadline of the next frame
+
+	// audioBlocking tells if the audio backend can pace the frames
+	audioBlocking = audio.Blocking
+)
+
+// SetFPS sets the frame rate of the core and restarts the schedule
+func SetFPS(f float64) {
+	if f <= 0 {
+		f = 60
+	}
+	fps = f
+	Reset()
+}
+
+// Reset restarts the schedule, it should be called when the game is paused so
+// the missed frames are not caught up
+func Reset() {
+	next = time.Time{}
+}
+
+// ratio returns the speed of the game, more than 1 when fast forwarding
+func ratio() float64 {
+	if state.FastForward && settings.Current.FastForwardRatio > 1 {
+		return float64(settings.Current.FastForwardRatio)
+	}
+	return 1
+}
+
+// timed returns true if the frames are paced by the clock. In audio sync mode,
+// the clock is only used when fast forwarding, as the audio is muted, or when
+// the audio backend never blocks, like the Null and WAV backends.
+func timed() bool {
+	return settings.Current.VideoSyncMode != AudioSync || state.FastForward || !audioBlocking()
+}
+
+// locked returns true if the core runs one frame per display refresh
+func locked(refresh float64) bool {
+	return settings.Current.VideoSyncMode == VSync && ratio() == 1 &&
+		refresh > 0 && math.Abs(fps-refresh)/refresh < lockThreshold
+}
+
+// SwapInterval returns the swap interval for the sync mode. The menu always
+// waits for the display refresh.
+func SwapInterval() int {
+	if settings.Current.VideoSyncMode == VSync || state.MenuActive || !state.CoreRunning {
+		return 1
+	}
+	return 0
+}
+
+// Due returns the number of core frames to run at now. refresh is the refresh
+// rate of the display. Only the last frame is rendered, so up to VideoFrameSkip
+// frames can be skipped to catch up when the emulation is late, the game slows
+// down past that.
+func Due(now time.Time, refresh float64) int {
+	if !timed() || locked(refresh) {
+		next = now
+		return 1
+	}
+
+	period := time.Duration(float64(time.Second) / (fps * ratio()))
+	if next.IsZero() {
+		next = now
+	}
+
+	limit := int(math.Ceil(ratio())) + settings.Current.VideoFrameSkip
+	n := 0
+	for n < limit && !now.Before(next) {
+		n++
+		next = next.Add(period)
+	}
+	if now.Sub(next) > period {
+		next = now.Add(period)
+	}
+	return n
+}
+
+// Wait returns how long to wait until the next frame is due
+func Wait(now time.Time) time.Duration {
+	if next.Before(now) {
+		return 0
+	}
+	return next.Sub(now)
+}
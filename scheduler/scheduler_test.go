@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libretro/ludo/audio"
+	"github.com/libretro/ludo/settings"
+	"github.com/libretro/ludo/state"
+)
+
+// run calls Due at each refresh of a display for a second, and returns the
+// number of frames run
+func run(refresh float64) int {
+	start := time.Unix(0, 0)
+	total := 0
+	for i := 0; i < int(refresh); i++ {
+		now := start.Add(time.Duration(float64(i) * float64(time.Second) / refresh))
+		total += Due(now, refresh)
+	}
+	return total
+}
+
+func TestDue(t *testing.T) {
+	settings.Current.FastForwardRatio = 4
+	defer func() {
+		state.FastForward = false
+		audioBlocking = audio.Blocking
+	}()
+
+	tests := []struct {
+		name        string
+		mode        string
+		fps         float64
+		refresh     float64
+		fastForward bool
+		blocking    bool
+		want        int
+	}{
+		{"Runs a PAL game at its speed on a 60 Hz display", VSync, 50, 60, false, false, 50},
+		{"Runs a game at its speed on a 144 Hz display", VSync, 60, 144, false, false, 60},
+		{"Fast forwards at the ratio", VSync, 60, 60, true, false, 240},
+		{"Paces the free mode with the clock", Free, 50, 1000, false, false, 50},
+		{"Lets the audio pace the frames", AudioSync, 50, 1000, false, true, 1000},
+		{"Paces the audio sync mode when fast forwarding", AudioSync, 50, 1000, true, true, 200},
+		{"Paces the audio sync mode when the audio doesn't block", AudioSync, 50, 1000, false, false, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings.Current.VideoSyncMode = tt.mode
+			state.FastForward = tt.fastForward
+			audioBlocking = func() bool { return tt.blocking }
+			SetFPS(tt.fps)
+			if got := run(tt.refresh); got < tt.want-4 || got > tt.want+4 {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDue_frameSkip(t *testing.T) {
+	settings.Current.VideoSyncMode = Free
+	start := time.Unix(0, 0)
+	late := start.Add(100 * time.Millisecond)
+
+	t.Run("Slows down without frame skip", func(t *testing.T) {
+		settings.Current.VideoFrameSkip = 0
+		SetFPS(50)
+		Due(start, 0)
+		if got := Due(late, 0); got != 1 {
+			t.Errorf("got = %v, want %v", got, 1)
+		}
+		if got := Wait(late); got != 20*time.Millisecond {
+			t.Errorf("got = %v, want %v", got, 20*time.Millisecond)
+		}
+	})
+
+	t.Run("Catches up with frame skip", func(t *testing.T) {
+		settings.Current.VideoFrameSkip = 2
+		SetFPS(50)
+		Due(start, 0)
+		if got := Due(late, 0); got != 3 {
+			t.Errorf("got = %v, want %v", got, 3)
+		}
+	})
+
+	settings.Current.VideoFrameSkip = 0
+}
+
+func Test_locked(t *testing.T) {
+	settings.Current.VideoSyncMode = VSync
+	tests := []struct {
+		name    string
+		fps     float64
+		refresh float64
+		want    bool
+	}{
+		{"Locks to a display that is close enough", 60.0988, 60, true},
+		{"Doesn't lock a PAL game", 50, 60, false},
+		{"Doesn't lock to an unknown display", 60, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetFPS(tt.fps)
+			if got := locked(tt.refresh); got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSwapInterval(t *testing.T) {
+	state.CoreRunning = true
+	defer func() { state.CoreRunning = false }()
+
+	tests := []struct {
+		mode string
+		want int
+	}{
+		{VSync, 1},
+		{AudioSync, 0},
+		{Free, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			settings.Current.VideoSyncMode = tt.mode
+			if got := SwapInterval(); got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
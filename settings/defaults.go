@@ -28,13 +28,26 @@ func defaultSettings() Settings {
 	}
 
 	return Settings{
-		VideoFullscreen:   false,
-		VideoMonitorIndex: 0,
-		VideoFilter:       "Pixel Perfect",
-		MapAxisToDPad:     false,
-		AudioVolume:       0.5,
-		MenuAudioVolume:   0.25,
-		ShowHiddenFiles:   false,
+		VideoFullscreen:     false,
+		VideoMonitorIndex:   0,
+		VideoFilter:         "Pixel Perfect",
+		VideoAspect:         "Core",
+		VideoCustomAspect:   1.33,
+		VideoIntegerScale:   false,
+		VideoCropOverscan:   false,
+		VideoSyncMode:       "VSync",
+		VideoFrameSkip:      0,
+		FastForwardRatio:    4,
+		MapAxisToDPad:       false,
+		InputRumbleStrength: 1,
+		RewindEnabled:       false,
+		RewindBufferSize:    64,
+		RewindInterval:      1,
+		AutoSavestate:       false,
+		AudioVolume:         0.5,
+		AudioDriver:         "OpenAL",
+		MenuAudioVolume:     0.25,
+		ShowHiddenFiles:     false,
 		CoreForPlaylist: map[string]string{
 			"Atari - 2600":                                   "stella2014_libretro",
 			"Atari - 5200":                                   "atari800_libretro",
@@ -78,14 +91,29 @@ func defaultSettings() Settings {
 			"SNK - Neo Geo Pocket":                           "mednafen_ngp_libretro",
 			"Sony - PlayStation":                             playstationCore,
 		},
-		CoresDirectory:       coresDir(),
-		AssetsDirectory:      "./assets",
-		DatabaseDirectory:    "./database",
-		SavestatesDirectory:  filepath.Join(home, ".ludo", "savestates"),
-		SavefilesDirectory:   filepath.Join(home, ".ludo", "savefiles"),
-		ScreenshotsDirectory: filepath.Join(home, ".ludo", "screenshots"),
-		SystemDirectory:      filepath.Join(home, ".ludo", "system"),
-		PlaylistsDirectory:   filepath.Join(home, ".ludo", "playlists"),
-		ThumbnailsDirectory:  filepath.Join(home, ".ludo", "thumbnails"),
+		DisabledPatches:       map[string]bool{},
+		CoresDirectory:        coresDir(),
+		AssetsDirectory:       "./assets",
+		DatabaseDirectory:     "./database",
+		SavestatesDirectory:   filepath.Join(home, ".ludo", "savestates"),
+		SavefilesDirectory:    filepath.Join(home, ".ludo", "savefiles"),
+		ScreenshotsDirectory:  filepath.Join(home, ".ludo", "screenshots"),
+		ShadersDirectory:      filepath.Join(home, ".ludo", "shaders"),
+		MoviesDirectory:       filepath.Join(home, ".ludo", "movies"),
+		PatchesDirectory:      filepath.Join(home, ".ludo", "patches"),
+		CheatsDirectory:       filepath.Join(home, ".ludo", "cheats"),
+		AchievementsDirectory: filepath.Join(home, ".ludo", "achievements"),
+		RemapsDirectory:       filepath.Join(home, ".ludo", "remaps"),
+		SystemDirectory:       filepath.Join(home, ".ludo", "system"),
+		PlaylistsDirectory:    filepath.Join(home, ".ludo", "playlists"),
+		ThumbnailsDirectory:   filepath.Join(home, ".ludo", "thumbnails"),
+		RecordingsDirectory:   filepath.Join(home, ".ludo", "recordings"),
+		CoreDisplay:           map[string]Display{},
+		OverscanCrop: map[string]Crop{
+			"FCEUmm":   {Left: 8, Right: 8, Top: 8, Bottom: 8},
+			"Mesen":    {Left: 8, Right: 8, Top: 8, Bottom: 8},
+			"Nestopia": {Left: 8, Right: 8, Top: 8, Bottom: 8},
+			"QuickNES": {Left: 8, Right: 8, Top: 8, Bottom: 8},
+		},
 	}
 }
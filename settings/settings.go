@@ -21,35 +21,92 @@ import (
 // Tags are used to set a human readable label and a format for the settings value.
 // Widget sets the graphical representation of the value.
 type Settings struct {
-	VideoFullscreen   bool   `hide:"ludos" toml:"video_fullscreen" label:"Video Fullscreen" fmt:"%t" widget:"switch"`
-	VideoMonitorIndex int    `toml:"video_monitor_index" label:"Video Monitor Index" fmt:"%d"`
-	VideoFilter       string `toml:"video_filter" label:"Video Filter" fmt:"<%s>"`
-	VideoDarkMode     bool   `toml:"video_dark_mode" label:"Video Dark Mode" fmt:"%t" widget:"switch"`
+	VideoFullscreen   bool    `hide:"ludos" toml:"video_fullscreen" label:"Video Fullscreen" fmt:"%t" widget:"switch"`
+	VideoMonitorIndex int     `toml:"video_monitor_index" label:"Video Monitor Index" fmt:"%d"`
+	VideoFilter       string  `toml:"video_filter" label:"Video Filter" fmt:"<%s>"`
+	VideoDarkMode     bool    `toml:"video_dark_mode" label:"Video Dark Mode" fmt:"%t" widget:"switch"`
+	VideoAspect       string  `toml:"video_aspect" label:"Video Aspect Ratio" fmt:"<%s>"`
+	VideoCustomAspect float32 `toml:"video_custom_aspect" label:"Video Custom Aspect Ratio" fmt:"%.2f"`
+	VideoIntegerScale bool    `toml:"video_integer_scale" label:"Video Integer Scaling" fmt:"%t" widget:"switch"`
+	VideoCropOverscan bool    `toml:"video_crop_overscan" label:"Video Crop Overscan" fmt:"%t" widget:"switch"`
+	VideoSyncMode     string  `toml:"video_sync_mode" label:"Video Sync Mode" fmt:"<%s>"`
+	VideoFrameSkip    int     `toml:"video_frame_skip" label:"Video Frame Skip" fmt:"%d"`
+	FastForwardRatio  int     `toml:"fast_forward_ratio" label:"Fast Forward Ratio" fmt:"%dx"`
 
 	AudioVolume float32 `toml:"audio_volume" label:"Audio Volume" fmt:"%.1f" widget:"range"`
+	AudioDriver string  `toml:"audio_driver" label:"Audio Driver" fmt:"<%s>"`
 
 	MenuAudioVolume float32 `toml:"menu_audio_volume" label:"Menu Audio Volume" fmt:"%.1f" widget:"range"`
 	ShowHiddenFiles bool    `toml:"menu_showhiddenfiles" label:"Show Hidden Files" fmt:"%t" widget:"switch"`
 
-	MapAxisToDPad bool `toml:"input_map_axis_to_dpad" label:"Map Sticks To DPad" fmt:"%t" widget:"switch"`
-
-	CoreForPlaylist map[string]string `hide:"always" toml:"core_for_playlist"`
-
-	CoresDirectory       string `hide:"ludos" toml:"cores_dir" label:"Cores Directory" fmt:"%s" widget:"dir"`
-	AssetsDirectory      string `hide:"ludos" toml:"assets_dir" label:"Assets Directory" fmt:"%s" widget:"dir"`
-	DatabaseDirectory    string `hide:"ludos" toml:"database_dir" label:"Database Directory" fmt:"%s" widget:"dir"`
-	SavestatesDirectory  string `hide:"ludos" toml:"savestates_dir" label:"Savestates Directory" fmt:"%s" widget:"dir"`
-	SavefilesDirectory   string `hide:"ludos" toml:"savefiles_dir" label:"Savefiles Directory" fmt:"%s" widget:"dir"`
-	ScreenshotsDirectory string `hide:"ludos" toml:"screenshots_dir" label:"Screenshots Directory" fmt:"%s" widget:"dir"`
-	SystemDirectory      string `hide:"ludos" toml:"system_dir" label:"System Directory" fmt:"%s" widget:"dir"`
-	PlaylistsDirectory   string `hide:"ludos" toml:"playlists_dir" label:"Playlists Directory" fmt:"%s" widget:"dir"`
-	ThumbnailsDirectory  string `hide:"ludos" toml:"thumbnail_dir" label:"Thumbnails Directory" fmt:"%s" widget:"dir"`
+	MapAxisToDPad       bool    `toml:"input_map_axis_to_dpad" label:"Map Sticks To DPad" fmt:"%t" widget:"switch"`
+	InputRumbleStrength float32 `toml:"input_rumble_strength" label:"Rumble Strength" fmt:"%.1f" widget:"range"`
+
+	RewindEnabled    bool `toml:"rewind_enabled" label:"Rewind" fmt:"%t" widget:"switch"`
+	RewindBufferSize int  `toml:"rewind_buffer_size" label:"Rewind Buffer Size" fmt:"%d MB"`
+	RewindInterval   int  `toml:"rewind_interval" label:"Rewind Granularity" fmt:"%d frames"`
+
+	AutoSavestate bool `toml:"auto_savestate" label:"Auto Save State" fmt:"%t" widget:"switch"`
+
+	CoreForPlaylist map[string]string  `hide:"always" toml:"core_for_playlist"`
+	DisabledPatches map[string]bool    `hide:"always" toml:"disabled_patches"`
+	CoreDisplay     map[string]Display `hide:"always" toml:"core_display"`
+	OverscanCrop    map[string]Crop    `hide:"always" toml:"overscan_crop"`
+
+	CoresDirectory        string `hide:"ludos" toml:"cores_dir" label:"Cores Directory" fmt:"%s" widget:"dir"`
+	AssetsDirectory       string `hide:"ludos" toml:"assets_dir" label:"Assets Directory" fmt:"%s" widget:"dir"`
+	DatabaseDirectory     string `hide:"ludos" toml:"database_dir" label:"Database Directory" fmt:"%s" widget:"dir"`
+	SavestatesDirectory   string `hide:"ludos" toml:"savestates_dir" label:"Savestates Directory" fmt:"%s" widget:"dir"`
+	SavefilesDirectory    string `hide:"ludos" toml:"savefiles_dir" label:"Savefiles Directory" fmt:"%s" widget:"dir"`
+	ScreenshotsDirectory  string `hide:"ludos" toml:"screenshots_dir" label:"Screenshots Directory" fmt:"%s" widget:"dir"`
+	ShadersDirectory      string `hide:"ludos" toml:"shaders_dir" label:"Shaders Directory" fmt:"%s" widget:"dir"`
+	MoviesDirectory       string `hide:"ludos" toml:"movies_dir" label:"Movies Directory" fmt:"%s" widget:"dir"`
+	PatchesDirectory      string `hide:"ludos" toml:"patches_dir" label:"Patches Directory" fmt:"%s" widget:"dir"`
+	CheatsDirectory       string `hide:"ludos" toml:"cheats_dir" label:"Cheats Directory" fmt:"%s" widget:"dir"`
+	AchievementsDirectory string `hide:"ludos" toml:"achievements_dir" label:"Achievements Directory" fmt:"%s" widget:"dir"`
+	RemapsDirectory       string `hide:"ludos" toml:"remaps_dir" label:"Remaps Directory" fmt:"%s" widget:"dir"`
+	SystemDirectory       string `hide:"ludos" toml:"system_dir" label:"System Directory" fmt:"%s" widget:"dir"`
+	PlaylistsDirectory    string `hide:"ludos" toml:"playlists_dir" label:"Playlists Directory" fmt:"%s" widget:"dir"`
+	ThumbnailsDirectory   string `hide:"ludos" toml:"thumbnail_dir" label:"Thumbnails Directory" fmt:"%s" widget:"dir"`
+	RecordingsDirectory   string `hide:"ludos" toml:"recordings_dir" label:"Recordings Directory" fmt:"%s" widget:"dir"`
 
 	SSHService       bool `hide:"app" toml:"ssh_service" label:"SSH" widget:"switch" service:"sshd.service" path:"/storage/.cache/services/sshd.conf"`
 	SambaService     bool `hide:"app" toml:"samba_service" label:"Samba" widget:"switch" service:"smbd.service" path:"/storage/.cache/services/samba.conf"`
 	BluetoothService bool `hide:"app" toml:"bluetooth_service" label:"Bluetooth" widget:"switch" service:"bluetooth.service" path:"/storage/.cache/services/bluez.conf"`
 }
 
+// Display holds the options of the game image. The global ones are the Video
+// settings, cores can override them in CoreDisplay.
+type Display struct {
+	Aspect       string  `toml:"aspect"`
+	CustomAspect float32 `toml:"custom_aspect"`
+	IntegerScale bool    `toml:"integer_scale"`
+	CropOverscan bool    `toml:"crop_overscan"`
+}
+
+// Crop holds the borders trimmed from the game image when cropping the
+// overscan, in pixels of the base geometry of the core
+type Crop struct {
+	Left   int `toml:"left"`
+	Right  int `toml:"right"`
+	Top    int `toml:"top"`
+	Bottom int `toml:"bottom"`
+}
+
+// DisplayFor returns the display options of a core, its own if it has some,
+// or the global ones
+func DisplayFor(core string) Display {
+	if d, ok := Current.CoreDisplay[core]; ok {
+		return d
+	}
+	return Display{
+		Aspect:       Current.VideoAspect,
+		CustomAspect: Current.VideoCustomAspect,
+		IntegerScale: Current.VideoIntegerScale,
+		CropOverscan: Current.VideoCropOverscan,
+	}
+}
+
 // Current stores the current settings at runtime
 var Current Settings
 
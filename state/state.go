@@ -25,6 +25,11 @@ var CorePath string
 // GamePath is the path of the current game
 var GamePath string
 
+// ContentName is the path the files of a game made of several roms, like a
+// subsystem game, are named after. These games have no GamePath, as they
+// can't be reloaded from a single path.
+var ContentName string
+
 // DB is the game database loaded on startup
 var DB dat.DB
 
@@ -33,3 +38,22 @@ var LudOS bool
 
 // FastForward will run the core as fast as possible
 var FastForward bool
+
+// GameFocus gives the keyboard to the game. Keyboard hot keys are disabled.
+var GameFocus bool
+
+// Headless is whether the core runs without window, audio device and inputs
+var Headless bool
+
+// ContentPath returns the path the files of the running game are named after,
+// like savefiles, savestates and screenshots. A core running without a game
+// names them after itself.
+func ContentPath() string {
+	if ContentName != "" {
+		return ContentName
+	}
+	if GamePath == "" {
+		return CorePath
+	}
+	return GamePath
+}
@@ -33,8 +33,28 @@ func IndexOfString(element string, data []string) int {
 	return 0
 }
 
-// FileName returns the name of a file, without the path and extension.
+// ArchiveExts are the extensions of the archives that games can be loaded from
+var ArchiveExts = []string{".zip", ".7z"}
+
+// SplitArchivePath splits a path like roms/game.zip#game.sfc, pointing to a
+// file inside an archive, into the path of the archive and the path of the
+// file inside the archive. The archive path is empty for other paths.
+func SplitArchivePath(path string) (archive, entry string) {
+	lower := strings.ToLower(path)
+	for _, ext := range ArchiveExts {
+		if i := strings.Index(lower, ext+"#"); i >= 0 {
+			return path[:i+len(ext)], path[i+len(ext)+1:]
+		}
+	}
+	return "", ""
+}
+
+// FileName returns the name of a file, without the path and extension. Files
+// inside archives are named after themselves, not after the archive.
 func FileName(path string) string {
+	if _, entry := SplitArchivePath(path); entry != "" {
+		path = entry
+	}
 	name := filepath.Base(path)
 	ext := filepath.Ext(name)
 	name = name[0 : len(name)-len(ext)]
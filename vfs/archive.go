@@ -0,0 +1,192 @@
+package vfs
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// entry is a file or a directory listed in an archive. The names of the
+// directories end with a slash, like in zip archives.
+type entry struct {
+	name string
+	info os.FileInfo
+}
+
+// archiveFile is a file of an archive, read-only
+type archiveFile struct {
+	readSeeker
+	info os.FileInfo
+}
+
+type readSeeker interface {
+	Read(p []byte) (int, error)
+	Seek(offset int64, whence int) (int64, error)
+}
+
+func (f *archiveFile) Write(p []byte) (int, error) { return 0, ErrReadOnly }
+func (f *archiveFile) Truncate(size int64) error   { return ErrReadOnly }
+func (f *archiveFile) Sync() error                 { return nil }
+func (f *archiveFile) Close() error                { return nil }
+func (f *archiveFile) Stat() (os.FileInfo, error)  { return f.info, nil }
+
+// dirInfo describes a directory of an archive. Archives don't always have
+// entries for their directories.
+type dirInfo struct {
+	name string
+}
+
+func (d dirInfo) Name() string       { return d.name }
+func (d dirInfo) Size() int64        { return 0 }
+func (d dirInfo) Mode() os.FileMode  { return os.ModeDir | 0555 }
+func (d dirInfo) ModTime() time.Time { return time.Time{} }
+func (d dirInfo) IsDir() bool        { return true }
+func (d dirInfo) Sys() interface{}   { return nil }
+
+// fileInfo describes a file of an archive that has no os.FileInfo of its own
+type fileInfo struct {
+	name string
+	size int64
+}
+
+func (f fileInfo) Name() string       { return f.name }
+func (f fileInfo) Size() int64        { return f.size }
+func (f fileInfo) Mode() os.FileMode  { return 0444 }
+func (f fileInfo) ModTime() time.Time { return time.Time{} }
+func (f fileInfo) IsDir() bool        { return false }
+func (f fileInfo) Sys() interface{}   { return nil }
+
+// clean converts a path inside an archive to the zip format
+func clean(name string) string {
+	name = path.Clean("/" + strings.Replace(name, "\\", "/", -1))
+	return strings.TrimPrefix(name, "/")
+}
+
+// pathError builds the errors returned for a file of an archive
+func pathError(archive, op, name string, err error) error {
+	return &os.PathError{Op: op, Path: archive + Separator + name, Err: err}
+}
+
+// files lists the regular files of an archive, in the order of the archive
+func files(entries []entry) []string {
+	files := []string{}
+	for _, e := range entries {
+		if !e.info.IsDir() && !strings.HasSuffix(e.name, "/") {
+			files = append(files, e.name)
+		}
+	}
+	return files
+}
+
+// stat describes a file or a directory of an archive
+func stat(entries []entry, archive, name string) (os.FileInfo, error) {
+	name = clean(name)
+	if name == "" {
+		return dirInfo{path.Base(archive)}, nil
+	}
+	for _, e := range entries {
+		if strings.TrimSuffix(e.name, "/") == name {
+			return e.info, nil
+		}
+		if strings.HasPrefix(e.name, name+"/") {
+			return dirInfo{path.Base(name)}, nil
+		}
+	}
+	return nil, pathError(archive, "stat", name, os.ErrNotExist)
+}
+
+// readDir lists the files and directories in a directory of an archive
+func readDir(entries []entry, archive, name string) ([]os.FileInfo, error) {
+	prefix := clean(name)
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	found := prefix == ""
+	infos := []os.FileInfo{}
+	dirs := map[string]bool{}
+	for _, e := range entries {
+		if !strings.HasPrefix(e.name, prefix) {
+			continue
+		}
+		found = true
+		rel := strings.TrimPrefix(e.name, prefix)
+		if rel == "" {
+			continue
+		}
+		if i := strings.Index(rel, "/"); i >= 0 {
+			// A file in a subdirectory, or the entry of a subdirectory
+			dir := rel[:i]
+			if !dirs[dir] {
+				dirs[dir] = true
+				infos = append(infos, dirInfo{dir})
+			}
+			continue
+		}
+		infos = append(infos, e.info)
+	}
+	if !found {
+		return nil, pathError(archive, "readdir", name, os.ErrNotExist)
+	}
+	return infos, nil
+}
+
+var (
+	mutex sync.Mutex
+
+	// unpacked is the last compressed file that was opened. Cores often open
+	// the same file several times, like the tracks of a CD image.
+	unpacked struct {
+		archive string
+		name    string
+		data    []byte
+	}
+)
+
+// cachedData returns the content of a compressed file if it was the last one
+// opened
+func cachedData(archive, name string) ([]byte, bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if unpacked.data != nil && unpacked.archive == archive && unpacked.name == name {
+		return unpacked.data, true
+	}
+	return nil, false
+}
+
+// cacheData keeps the content of the last compressed file opened
+func cacheData(archive, name string, data []byte) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	unpacked.archive, unpacked.name, unpacked.data = archive, name, data
+}
+
+// forget drops what is cached about an archive, after it changed on disk
+func forget(archive string) {
+	if unpacked.archive == archive {
+		unpacked.archive, unpacked.name, unpacked.data = "", "", nil
+	}
+}
+
+// CloseArchives closes the archives kept open by the views and frees their
+// caches. It should be called when a game is unloaded.
+func CloseArchives() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for archive, z := range zips {
+		z.fd.Close()
+		delete(zips, archive)
+	}
+	for archive := range sevenZips {
+		delete(sevenZips, archive)
+	}
+	unpacked.archive, unpacked.name, unpacked.data = "", "", nil
+}
+
+// newDataFile serves a file of an archive from memory
+func newDataFile(data []byte, info os.FileInfo) *archiveFile {
+	return &archiveFile{bytes.NewReader(data), info}
+}
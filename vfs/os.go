@@ -0,0 +1,68 @@
+package vfs
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/libretro/ludo/libretro"
+)
+
+// OS serves the files of the OS file system
+type OS struct{}
+
+// flags converts the libretro.VFSFileAccess flags to os.OpenFile flags. Files
+// opened for writing are created, and truncated unless the existing content
+// has to be kept.
+func flags(mode uint) int {
+	var f int
+	switch mode & libretro.VFSFileAccessReadWrite {
+	case libretro.VFSFileAccessReadWrite:
+		f = os.O_RDWR | os.O_CREATE
+	case libretro.VFSFileAccessWrite:
+		f = os.O_WRONLY | os.O_CREATE
+	default:
+		return os.O_RDONLY
+	}
+	if mode&libretro.VFSFileAccessUpdateExisting == 0 {
+		f |= os.O_TRUNC
+	}
+	return f
+}
+
+// Open opens a file with the libretro.VFSFileAccess flags in mode
+func (OS) Open(path string, mode uint) (libretro.VFSFile, error) {
+	fi, err := os.Stat(path)
+	if err == nil && fi.IsDir() {
+		return nil, &os.PathError{Op: "open", Path: path, Err: errIsDir}
+	}
+	f, err := os.OpenFile(path, flags(mode), 0644)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Remove removes a file or an empty directory
+func (OS) Remove(path string) error {
+	return os.Remove(path)
+}
+
+// Rename renames a file
+func (OS) Rename(oldPath, newPath string) error {
+	return os.Rename(oldPath, newPath)
+}
+
+// Stat describes a file
+func (OS) Stat(path string) (os.FileInfo, error) {
+	return os.Stat(path)
+}
+
+// Mkdir creates a directory
+func (OS) Mkdir(path string) error {
+	return os.Mkdir(path, 0755)
+}
+
+// ReadDir lists the files of a directory
+func (OS) ReadDir(path string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(path)
+}
@@ -0,0 +1,193 @@
+package vfs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libretro/ludo/libretro"
+)
+
+// SevenZip is a read-only view of a 7z archive. Paths are relative to the root
+// of the archive. Go has no 7z decoder, so the archive is read with the 7z
+// command of 7-Zip or p7zip, which has to be installed.
+type SevenZip struct {
+	Archive string // path of the archive on the OS file system
+}
+
+// ErrNoSevenZip is returned when 7z archives are used without 7-Zip installed
+var ErrNoSevenZip = errors.New("7z archives need 7-Zip or p7zip to be installed")
+
+// sevenZipListing is the listing of a 7z archive, kept until the archive
+// changes on disk or CloseArchives
+type sevenZipListing struct {
+	entries []entry
+	modTime time.Time
+	size    int64
+}
+
+// sevenZips are the listings of the 7z archives, by path
+var sevenZips = map[string]sevenZipListing{}
+
+// SevenZipCommand returns the path of the 7-Zip command used to read 7z
+// archives
+func SevenZipCommand() (string, error) {
+	for _, name := range []string{"7z", "7za", "7zr"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", ErrNoSevenZip
+}
+
+// parse7zList reads the technical listing of 7z l -slt
+func parse7zList(out []byte) []entry {
+	entries := []entry{}
+	started := false
+	name, size, folder := "", int64(0), false
+	flush := func() {
+		if name != "" {
+			name = filepath.ToSlash(name)
+			if folder {
+				entries = append(entries, entry{name + "/", dirInfo{path.Base(name)}})
+			} else {
+				entries = append(entries, entry{name, fileInfo{path.Base(name), size}})
+			}
+		}
+		name, size, folder = "", 0, false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "----------"):
+			// The entries come after the description of the archive
+			started = true
+		case !started:
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "Path = "):
+			name = strings.TrimPrefix(line, "Path = ")
+		case strings.HasPrefix(line, "Size = "):
+			size, _ = strconv.ParseInt(strings.TrimPrefix(line, "Size = "), 10, 64)
+		case line == "Folder = +", strings.HasPrefix(line, "Attributes = D"):
+			folder = true
+		}
+	}
+	flush()
+	return entries
+}
+
+// list returns the listing of the archive, running 7z on first use or when
+// the archive changed on disk
+func (z SevenZip) list() ([]entry, error) {
+	fi, err := os.Stat(z.Archive)
+	if err != nil {
+		return nil, err
+	}
+
+	mutex.Lock()
+	l, ok := sevenZips[z.Archive]
+	mutex.Unlock()
+	if ok && l.modTime.Equal(fi.ModTime()) && l.size == fi.Size() {
+		return l.entries, nil
+	}
+
+	bin, err := SevenZipCommand()
+	if err != nil {
+		return nil, err
+	}
+	out, err := exec.Command(bin, "l", "-slt", "--", z.Archive).Output()
+	if err != nil {
+		return nil, err
+	}
+	entries := parse7zList(out)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	forget(z.Archive)
+	sevenZips[z.Archive] = sevenZipListing{entries, fi.ModTime(), fi.Size()}
+	return entries, nil
+}
+
+// Files lists the regular files of the archive, in the order of the archive
+func (z SevenZip) Files() ([]string, error) {
+	entries, err := z.list()
+	if err != nil {
+		return nil, err
+	}
+	return files(entries), nil
+}
+
+// Open decompresses a file of the archive in memory. Files can only be opened
+// for reading.
+func (z SevenZip) Open(name string, mode uint) (libretro.VFSFile, error) {
+	if mode&libretro.VFSFileAccessWrite != 0 {
+		return nil, pathError(z.Archive, "open", name, ErrReadOnly)
+	}
+
+	fi, err := z.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return nil, pathError(z.Archive, "open", name, errIsDir)
+	}
+
+	name = clean(name)
+	if data, ok := cachedData(z.Archive, name); ok {
+		return newDataFile(data, fi), nil
+	}
+	bin, err := SevenZipCommand()
+	if err != nil {
+		return nil, err
+	}
+	// -spd matches the name literally instead of as a wildcard
+	data, err := exec.Command(bin, "x", "-so", "-spd", "--", z.Archive, name).Output()
+	if err != nil {
+		return nil, err
+	}
+	cacheData(z.Archive, name, data)
+	return newDataFile(data, fi), nil
+}
+
+// Remove always fails, the archive is read-only
+func (z SevenZip) Remove(name string) error {
+	return pathError(z.Archive, "remove", name, ErrReadOnly)
+}
+
+// Rename always fails, the archive is read-only
+func (z SevenZip) Rename(oldName, newName string) error {
+	return pathError(z.Archive, "rename", oldName, ErrReadOnly)
+}
+
+// Mkdir always fails, the archive is read-only
+func (z SevenZip) Mkdir(name string) error {
+	return pathError(z.Archive, "mkdir", name, ErrReadOnly)
+}
+
+// Stat describes a file or a directory of the archive
+func (z SevenZip) Stat(name string) (os.FileInfo, error) {
+	entries, err := z.list()
+	if err != nil {
+		return nil, err
+	}
+	return stat(entries, z.Archive, name)
+}
+
+// ReadDir lists the files and directories in a directory of the archive
+func (z SevenZip) ReadDir(name string) ([]os.FileInfo, error) {
+	entries, err := z.list()
+	if err != nil {
+		return nil, err
+	}
+	return readDir(entries, z.Archive, name)
+}
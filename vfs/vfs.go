@@ -0,0 +1,91 @@
+// Package vfs implements the file system exposed to the libretro cores through
+// the VFS interface. Files inside zip and 7z archives are addressed with paths
+// like roms/game.zip#game.sfc and are served read-only from the archive, other
+// paths are served from the OS file system.
+package vfs
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/utils"
+)
+
+// Separator separates the path of an archive from the path of a file inside
+// the archive, see utils.SplitArchivePath
+const Separator = "#"
+
+// ErrReadOnly is returned when writing to a read-only backend
+var ErrReadOnly = errors.New("read-only file system")
+
+var errIsDir = errors.New("is a directory")
+
+// FS is the file system given to the cores. It dispatches the paths to the OS,
+// Zip and SevenZip backends.
+type FS struct{}
+
+// resolve returns the backend serving a path, and the path in this backend
+func resolve(path string) (libretro.VFS, string) {
+	archive, name := utils.SplitArchivePath(path)
+	switch strings.ToLower(filepath.Ext(archive)) {
+	case ".zip":
+		return Zip{Archive: archive}, name
+	case ".7z":
+		return SevenZip{Archive: archive}, name
+	}
+	return OS{}, path
+}
+
+// Open opens a file with the libretro.VFSFileAccess flags in mode
+func (FS) Open(path string, mode uint) (libretro.VFSFile, error) {
+	b, p := resolve(path)
+	return b.Open(p, mode)
+}
+
+// Remove removes a file or an empty directory
+func (FS) Remove(path string) error {
+	b, p := resolve(path)
+	return b.Remove(p)
+}
+
+// Rename renames a file. Files can't be moved between backends.
+func (FS) Rename(oldPath, newPath string) error {
+	b, o := resolve(oldPath)
+	nb, n := resolve(newPath)
+	if b != nb {
+		return ErrReadOnly
+	}
+	return b.Rename(o, n)
+}
+
+// Stat describes a file
+func (FS) Stat(path string) (os.FileInfo, error) {
+	b, p := resolve(path)
+	return b.Stat(p)
+}
+
+// Mkdir creates a directory
+func (FS) Mkdir(path string) error {
+	b, p := resolve(path)
+	return b.Mkdir(p)
+}
+
+// ReadDir lists the files of a directory
+func (FS) ReadDir(path string) ([]os.FileInfo, error) {
+	b, p := resolve(path)
+	return b.ReadDir(p)
+}
+
+// ReadFile reads a whole file, from the OS file system or from an archive
+func ReadFile(path string) ([]byte, error) {
+	f, err := FS{}.Open(path, libretro.VFSFileAccessRead)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
@@ -0,0 +1,289 @@
+package vfs
+
+import (
+	"archive/zip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/libretro/ludo/libretro"
+)
+
+// makeZip creates an archive with a file at the root and a file in a
+// directory that has no entry of its own
+func makeZip(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "ludo-vfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "game.zip")
+	fd, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(fd)
+	files := [][2]string{
+		{"game.cue", "cue"},
+		{"tracks/1.bin", "track one"},
+		{"tracks/2.bin", "track two"},
+		{"tracks/sub/3.x", ""},
+	}
+	for _, file := range files {
+		f, err := w.Create(file[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(file[1]))
+	}
+	w.Close()
+	fd.Close()
+	return path
+}
+
+func Test_resolve(t *testing.T) {
+	tests := []struct {
+		path     string
+		wantFS   interface{}
+		wantPath string
+	}{
+		{"roms/game.sfc", OS{}, "roms/game.sfc"},
+		{"roms/game.zip", OS{}, "roms/game.zip"},
+		{"roms/game.zip#game.sfc", Zip{Archive: "roms/game.zip"}, "game.sfc"},
+		{"roms/GAME.ZIP#dir/game.sfc", Zip{Archive: "roms/GAME.ZIP"}, "dir/game.sfc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			fs, path := resolve(tt.path)
+			if fs != tt.wantFS {
+				t.Errorf("got = %v, want %v", fs, tt.wantFS)
+			}
+			if path != tt.wantPath {
+				t.Errorf("got = %v, want %v", path, tt.wantPath)
+			}
+		})
+	}
+}
+
+func Test_flags(t *testing.T) {
+	tests := []struct {
+		name string
+		mode uint
+		want int
+	}{
+		{"Read", libretro.VFSFileAccessRead, os.O_RDONLY},
+		{"Write", libretro.VFSFileAccessWrite, os.O_WRONLY | os.O_CREATE | os.O_TRUNC},
+		{"Read write", libretro.VFSFileAccessReadWrite, os.O_RDWR | os.O_CREATE | os.O_TRUNC},
+		{"Update", libretro.VFSFileAccessReadWrite | libretro.VFSFileAccessUpdateExisting, os.O_RDWR | os.O_CREATE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flags(tt.mode); got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZip(t *testing.T) {
+	archive := makeZip(t)
+	defer os.RemoveAll(filepath.Dir(archive))
+	z := Zip{Archive: archive}
+
+	t.Run("Reads and seeks in a file", func(t *testing.T) {
+		f, err := FS{}.Open(archive+"#tracks/2.bin", libretro.VFSFileAccessRead)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.Seek(6, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		got, _ := ioutil.ReadAll(f)
+		if string(got) != "two" {
+			t.Errorf("got = %v, want %v", string(got), "two")
+		}
+		fi, _ := f.Stat()
+		if fi.Size() != 9 {
+			t.Errorf("got = %v, want %v", fi.Size(), 9)
+		}
+	})
+
+	t.Run("Is read-only", func(t *testing.T) {
+		if _, err := z.Open("game.cue", libretro.VFSFileAccessReadWrite); err == nil {
+			t.Errorf("got = %v, want an error", err)
+		}
+		if err := z.Remove("game.cue"); err == nil {
+			t.Errorf("got = %v, want an error", err)
+		}
+		if err := z.Mkdir("saves"); err == nil {
+			t.Errorf("got = %v, want an error", err)
+		}
+	})
+
+	t.Run("Doesn't open missing files", func(t *testing.T) {
+		_, err := z.Open("missing.bin", libretro.VFSFileAccessRead)
+		if !os.IsNotExist(err) {
+			t.Errorf("got = %v, want %v", err, os.ErrNotExist)
+		}
+	})
+
+	t.Run("Stats files and directories", func(t *testing.T) {
+		fi, err := z.Stat("game.cue")
+		if err != nil || fi.IsDir() || fi.Size() != 3 {
+			t.Errorf("got = %v, want a file of 3 bytes", fi)
+		}
+		fi, err = z.Stat("tracks")
+		if err != nil || !fi.IsDir() {
+			t.Errorf("got = %v, want a directory", fi)
+		}
+	})
+
+	t.Run("Lists directories", func(t *testing.T) {
+		names := func(path string) []string {
+			infos, err := z.ReadDir(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := []string{}
+			for _, fi := range infos {
+				names = append(names, fi.Name())
+			}
+			return names
+		}
+		got := names("")
+		want := []string{"game.cue", "tracks"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got = %v, want %v", got, want)
+		}
+		got = names("/tracks")
+		want = []string{"1.bin", "2.bin", "sub"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Lists the files of the archive", func(t *testing.T) {
+		got, err := z.Files()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 4 {
+			t.Errorf("got = %v, want 4 files", got)
+		}
+	})
+}
+
+func TestZip_cache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ludo-vfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer CloseArchives()
+	path := filepath.Join(dir, "game.zip")
+
+	write := func(content string, method uint16) {
+		fd, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := zip.NewWriter(fd)
+		f, err := w.CreateHeader(&zip.FileHeader{Name: "game.bin", Method: method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+		w.Close()
+		fd.Close()
+	}
+	read := func() string {
+		got, err := ReadFile(path + Separator + "game.bin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(got)
+	}
+
+	t.Run("Reads stored files from the archive", func(t *testing.T) {
+		write("stored", zip.Store)
+		if got := read(); got != "stored" {
+			t.Errorf("got = %v, want %v", got, "stored")
+		}
+	})
+
+	t.Run("Reopens the archive when it changes", func(t *testing.T) {
+		write("compressed file", zip.Deflate)
+		if got := read(); got != "compressed file" {
+			t.Errorf("got = %v, want %v", got, "compressed file")
+		}
+		if got := read(); got != "compressed file" {
+			t.Errorf("got = %v, want %v", got, "compressed file")
+		}
+	})
+
+	t.Run("Closes the archives", func(t *testing.T) {
+		CloseArchives()
+		if len(zips) != 0 || unpacked.data != nil {
+			t.Errorf("got = %v, want %v", len(zips), 0)
+		}
+	})
+}
+
+func TestReadFile(t *testing.T) {
+	archive := makeZip(t)
+	defer os.RemoveAll(filepath.Dir(archive))
+
+	got, err := ReadFile(archive + Separator + "tracks/1.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "track one" {
+		t.Errorf("got = %v, want %v", string(got), "track one")
+	}
+}
+
+func Test_parse7zList(t *testing.T) {
+	out := `7-Zip [64] 16.02 : Copyright (c) 1999-2016 Igor Pavlov : 2016-05-21
+
+Listing archive: games.7z
+
+--
+Path = games.7z
+Type = 7z
+Physical Size = 1204
+
+----------
+Path = Game (Disc 1).cue
+Size = 85
+Folder = -
+Attributes = A_ -rw-r--r--
+
+Path = tracks
+Size = 0
+Folder = +
+Attributes = D_ drwxr-xr-x
+
+Path = tracks/Game (Disc 1).bin
+Size = 4096
+Folder = -
+Attributes = A_ -rw-r--r--
+`
+	entries := parse7zList([]byte(out))
+	got := files(entries)
+	want := []string{"Game (Disc 1).cue", "tracks/Game (Disc 1).bin"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v, want %v", got, want)
+	}
+
+	fi, err := stat(entries, "games.7z", "tracks/Game (Disc 1).bin")
+	if err != nil || fi.Size() != 4096 || fi.IsDir() {
+		t.Errorf("got = %v, want a file of 4096 bytes", fi)
+	}
+	fi, err = stat(entries, "games.7z", "tracks")
+	if err != nil || !fi.IsDir() {
+		t.Errorf("got = %v, want a directory", fi)
+	}
+}
@@ -0,0 +1,153 @@
+package vfs
+
+import (
+	"archive/zip"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/libretro/ludo/libretro"
+)
+
+// Zip is a read-only view of a zip archive. Paths are relative to the root of
+// the archive.
+type Zip struct {
+	Archive string // path of the archive on the OS file system
+}
+
+// zipArchive is an open zip archive. Archives are kept open until
+// CloseArchives, as cores can open their files many times.
+type zipArchive struct {
+	fd      *os.File
+	r       *zip.Reader
+	entries []entry
+	modTime time.Time
+	size    int64
+}
+
+// zips are the open zip archives, by path
+var zips = map[string]*zipArchive{}
+
+// open returns the open archive, opening it on first use or when it changed
+// on disk
+func (z Zip) open() (*zipArchive, error) {
+	fi, err := os.Stat(z.Archive)
+	if err != nil {
+		return nil, err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if a, ok := zips[z.Archive]; ok {
+		if a.modTime.Equal(fi.ModTime()) && a.size == fi.Size() {
+			return a, nil
+		}
+		a.fd.Close()
+		delete(zips, z.Archive)
+		forget(z.Archive)
+	}
+
+	fd, err := os.Open(z.Archive)
+	if err != nil {
+		return nil, err
+	}
+	r, err := zip.NewReader(fd, fi.Size())
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	a := &zipArchive{fd: fd, r: r, modTime: fi.ModTime(), size: fi.Size()}
+	for _, f := range r.File {
+		a.entries = append(a.entries, entry{f.Name, f.FileInfo()})
+	}
+	zips[z.Archive] = a
+	return a, nil
+}
+
+// Files lists the regular files of the archive, in the order of the archive
+func (z Zip) Files() ([]string, error) {
+	a, err := z.open()
+	if err != nil {
+		return nil, err
+	}
+	return files(a.entries), nil
+}
+
+// Open opens a file of the archive. Files can only be opened for reading.
+// Stored files are read from the archive, compressed files are decompressed
+// in memory.
+func (z Zip) Open(name string, mode uint) (libretro.VFSFile, error) {
+	if mode&libretro.VFSFileAccessWrite != 0 {
+		return nil, pathError(z.Archive, "open", name, ErrReadOnly)
+	}
+
+	a, err := z.open()
+	if err != nil {
+		return nil, err
+	}
+
+	name = clean(name)
+	for _, f := range a.r.File {
+		if f.Name != name || f.FileInfo().IsDir() {
+			continue
+		}
+		if f.Method == zip.Store {
+			offset, err := f.DataOffset()
+			if err != nil {
+				return nil, err
+			}
+			sr := io.NewSectionReader(a.fd, offset, int64(f.UncompressedSize64))
+			return &archiveFile{sr, f.FileInfo()}, nil
+		}
+		if data, ok := cachedData(z.Archive, name); ok {
+			return newDataFile(data, f.FileInfo()), nil
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		data, err := ioutil.ReadAll(rc)
+		if err != nil {
+			return nil, err
+		}
+		cacheData(z.Archive, name, data)
+		return newDataFile(data, f.FileInfo()), nil
+	}
+	return nil, pathError(z.Archive, "open", name, os.ErrNotExist)
+}
+
+// Remove always fails, the archive is read-only
+func (z Zip) Remove(name string) error {
+	return pathError(z.Archive, "remove", name, ErrReadOnly)
+}
+
+// Rename always fails, the archive is read-only
+func (z Zip) Rename(oldName, newName string) error {
+	return pathError(z.Archive, "rename", oldName, ErrReadOnly)
+}
+
+// Mkdir always fails, the archive is read-only
+func (z Zip) Mkdir(name string) error {
+	return pathError(z.Archive, "mkdir", name, ErrReadOnly)
+}
+
+// Stat describes a file or a directory of the archive
+func (z Zip) Stat(name string) (os.FileInfo, error) {
+	a, err := z.open()
+	if err != nil {
+		return nil, err
+	}
+	return stat(a.entries, z.Archive, name)
+}
+
+// ReadDir lists the files and directories in a directory of the archive
+func (z Zip) ReadDir(name string) ([]os.FileInfo, error) {
+	a, err := z.open()
+	if err != nil {
+		return nil, err
+	}
+	return readDir(a.entries, z.Archive, name)
+}
@@ -0,0 +1,117 @@
+package video
+
+import (
+	"image"
+	"unsafe"
+
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/recording"
+)
+
+// frameToRGBA converts a frame, as passed by the core to Refresh, to an
+// image.RGBA. format is one of the libretro pixel formats.
+func frameToRGBA(format uint32, data []byte, width, height, pitch int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		line := data[y*pitch:]
+		for x := 0; x < width; x++ {
+			var r, g, b byte
+			switch format {
+			case libretro.PixelFormatXRGB8888:
+				b = line[x*4+0]
+				g = line[x*4+1]
+				r = line[x*4+2]
+			case libretro.PixelFormatRGB565:
+				v := uint16(line[x*2]) | uint16(line[x*2+1])<<8
+				r = byte(v>>11) & 0x1f
+				g = byte(v>>5) & 0x3f
+				b = byte(v) & 0x1f
+				r = r<<3 | r>>2
+				g = g<<2 | g>>4
+				b = b<<3 | b>>2
+			default: // libretro.PixelFormat0RGB1555
+				v := uint16(line[x*2]) | uint16(line[x*2+1])<<8
+				r = byte(v>>10) & 0x1f
+				g = byte(v>>5) & 0x1f
+				b = byte(v) & 0x1f
+				r = r<<3 | r>>2
+				g = g<<3 | g>>2
+				b = b<<3 | b>>2
+			}
+			i := img.PixOffset(x, y)
+			img.Pix[i+0] = r
+			img.Pix[i+1] = g
+			img.Pix[i+2] = b
+			img.Pix[i+3] = 0xff
+		}
+	}
+
+	return img
+}
+
+// refreshHeadless keeps a copy of the frame in memory instead of uploading it
+// to a GL texture
+func (video *Video) refreshHeadless(data unsafe.Pointer) {
+	// Frame duping, keep the previous frame
+	if data == nil {
+		return
+	}
+
+	size := int(video.pitch) * int(video.height)
+
+	// this *[1 << 30]byte points to the same memory as data, allowing to
+	// read the frame without copying it first
+	buf := (*[1 << 30]byte)(data)[:size:size]
+	video.frame = frameToRGBA(video.format, buf, int(video.width), int(video.height), int(video.pitch))
+}
+
+// recordFrame passes the frame to the recording, converted to RGBA. Hardware
+// rendered frames are read back from the framebuffer.
+func (video *Video) recordFrame(data unsafe.Pointer) {
+	w, h := int(video.width), int(video.height)
+	switch {
+	case data == nil:
+		// Frame duping, the recording repeats the previous frame
+		recording.Frame(nil)
+	case libretro.IsHWFrameBufferValid(data):
+		img := image.NewRGBA(image.Rect(0, 0, w, h))
+		var prev int32
+		gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &prev)
+		gl.BindFramebuffer(gl.FRAMEBUFFER, video.hw.fbo)
+		gl.ReadPixels(0, 0, video.width, video.height, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+		gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(prev))
+		if video.hw.bottomLeftOrigin {
+			flipRows(img)
+		}
+		recording.Frame(img)
+	default:
+		size := int(video.pitch) * h
+		buf := (*[1 << 30]byte)(data)[:size:size]
+		recording.Frame(frameToRGBA(video.format, buf, w, h, int(video.pitch)))
+	}
+}
+
+// flipRows flips an image upside down, in place
+func flipRows(img *image.RGBA) {
+	h := img.Rect.Dy()
+	tmp := make([]byte, img.Stride)
+	for y := 0; y < h/2; y++ {
+		top := img.Pix[y*img.Stride : (y+1)*img.Stride]
+		bottom := img.Pix[(h-1-y)*img.Stride : (h-y)*img.Stride]
+		copy(tmp, top)
+		copy(top, bottom)
+		copy(bottom, tmp)
+	}
+}
+
+// Frame returns the last frame rendered by the core in headless mode
+func (video *Video) Frame() *image.RGBA {
+	return video.frame
+}
+
+// Headless returns true if the video was initialized without a window
+func (video *Video) Headless() bool {
+	return video.headless
+}
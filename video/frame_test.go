@@ -0,0 +1,66 @@
+package video
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/libretro/ludo/libretro"
+)
+
+func Test_frameToRGBA(t *testing.T) {
+	tests := []struct {
+		name   string
+		format uint32
+		data   []byte
+		pitch  int
+		want   []byte
+	}{
+		{
+			name:   "Converts XRGB8888",
+			format: libretro.PixelFormatXRGB8888,
+			data:   []byte{0x30, 0x20, 0x10, 0x00, 0xff, 0xff, 0xff, 0x00},
+			pitch:  8,
+			want:   []byte{0x10, 0x20, 0x30, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			name:   "Converts RGB565",
+			format: libretro.PixelFormatRGB565,
+			data:   []byte{0x00, 0xf8, 0xe0, 0x07},
+			pitch:  4,
+			want:   []byte{0xff, 0x00, 0x00, 0xff, 0x00, 0xff, 0x00, 0xff},
+		},
+		{
+			name:   "Converts 0RGB1555",
+			format: libretro.PixelFormat0RGB1555,
+			data:   []byte{0x1f, 0x00, 0x00, 0x7c},
+			pitch:  4,
+			want:   []byte{0x00, 0x00, 0xff, 0xff, 0xff, 0x00, 0x00, 0xff},
+		},
+		{
+			name:   "Ignores the padding at the end of lines",
+			format: libretro.PixelFormatRGB565,
+			data:   []byte{0x00, 0x00, 0xff, 0xff, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff},
+			pitch:  5,
+			want:   []byte{0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := frameToRGBA(tt.format, tt.data, 2, len(tt.data)/tt.pitch, tt.pitch)
+			if !reflect.DeepEqual(got.Pix[:8], tt.want) {
+				t.Errorf("frameToRGBA() = %v, want %v", got.Pix[:8], tt.want)
+			}
+		})
+	}
+}
+
+func Test_flipRows(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 3))
+	copy(img.Pix, []byte{1, 1, 1, 1, 2, 2, 2, 2, 3, 3, 3, 3})
+	flipRows(img)
+	want := []byte{3, 3, 3, 3, 2, 2, 2, 2, 1, 1, 1, 1}
+	if !reflect.DeepEqual(img.Pix, want) {
+		t.Errorf("got = %v, want %v", img.Pix, want)
+	}
+}
@@ -65,6 +65,30 @@ func rotateUV(va []float32, rot uint) []float32 {
 	return va
 }
 
+// viewportToCore converts a point of the framebuffer to coordinates in the
+// game frame between 0 and 1, given the game viewport x, y, w, h and the
+// rotation of the game. It reverses the rotation done by rotateUV.
+func viewportToCore(px, py, x, y, w, h float32, rot uint) (u, v float32, inside bool) {
+	if w <= 0 || h <= 0 {
+		return 0, 0, false
+	}
+	sx := (px - x) / w
+	sy := (py - y) / h
+	inside = sx >= 0 && sx <= 1 && sy >= 0 && sy <= 1
+
+	switch rot {
+	case 1: // 90 degrees
+		u, v = 1-sy, sx
+	case 2: // 180 degrees
+		u, v = 1-sx, 1-sy
+	case 3: // 270 degrees
+		u, v = sy, 1-sx
+	default:
+		u, v = sx, sy
+	}
+	return
+}
+
 // DrawImage draws an image with x, y, w, h
 func (video *Video) DrawImage(image uint32, x, y, w, h float32, scale float32, c Color) {
 
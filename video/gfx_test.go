@@ -67,3 +67,36 @@ func TestXYWHTo4points(t *testing.T) {
 		})
 	}
 }
+
+func Test_viewportToCore(t *testing.T) {
+	// A 400x300 viewport placed at 100, 50. The point is a quarter from the
+	// left and a tenth from the top of the viewport.
+	px, py := float32(100+100), float32(50+30)
+	tests := []struct {
+		name   string
+		rot    uint
+		wantU  float32
+		wantV  float32
+		inside bool
+	}{
+		{name: "No rotation", rot: 0, wantU: 0.25, wantV: 0.1, inside: true},
+		{name: "90 degrees", rot: 1, wantU: 0.9, wantV: 0.25, inside: true},
+		{name: "180 degrees", rot: 2, wantU: 0.75, wantV: 0.9, inside: true},
+		{name: "270 degrees", rot: 3, wantU: 0.1, wantV: 0.75, inside: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, v, inside := viewportToCore(px, py, 100, 50, 400, 300, tt.rot)
+			if u != tt.wantU || v != tt.wantV || inside != tt.inside {
+				t.Errorf("viewportToCore() = %v, %v, %v, want %v, %v, %v", u, v, inside, tt.wantU, tt.wantV, tt.inside)
+			}
+		})
+	}
+
+	t.Run("Outside of the viewport", func(t *testing.T) {
+		_, _, inside := viewportToCore(90, 60, 100, 50, 400, 300, 0)
+		if inside {
+			t.Errorf("viewportToCore() inside = %v, want %v", inside, false)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package video
+
+import (
+	"log"
+	"unsafe"
+
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/state"
+)
+
+// hwRender holds the framebuffer object that hardware rendered cores draw to
+type hwRender struct {
+	enabled          bool // the core renders with OpenGL
+	depth, stencil   bool // the core requested a depth or stencil attachment
+	bottomLeftOrigin bool // the core renders with the OpenGL origin
+	valid            bool // the last frame was rendered in the framebuffer
+
+	fbo, tex, rbo     uint32
+	fbWidth, fbHeight int32 // size of the framebuffer texture
+}
+
+// SetHWRender checks if the hardware rendering context requested by the core
+// can be provided. Only OpenGL contexts are supported, and only if the
+// version of the window context is high enough.
+func (video *Video) SetHWRender(hw *libretro.HWRenderCallback) bool {
+	if video.headless {
+		return false
+	}
+
+	switch hw.ContextType {
+	case libretro.HWContextOpenGL:
+	case libretro.HWContextOpenGLCore:
+		major := uint(video.Window.GetAttrib(glfw.ContextVersionMajor))
+		minor := uint(video.Window.GetAttrib(glfw.ContextVersionMinor))
+		if major < hw.VersionMajor || major == hw.VersionMajor && minor < hw.VersionMinor {
+			log.Printf("[Video]: OpenGL %d.%d context not available\n", hw.VersionMajor, hw.VersionMinor)
+			return false
+		}
+	default:
+		log.Printf("[Video]: Unsupported hardware context type: %d\n", hw.ContextType)
+		return false
+	}
+
+	video.hw.enabled = true
+	video.hw.depth = hw.Depth
+	video.hw.stencil = hw.Stencil
+	video.hw.bottomLeftOrigin = hw.BottomLeftOrigin
+	return true
+}
+
+// InitFramebuffer creates the framebuffer object used by hardware rendered
+// cores. The size should be the maximum size of the game geometry.
+func (video *Video) InitFramebuffer(width, height int) {
+	if !video.hw.enabled {
+		return
+	}
+	video.DestroyFramebuffer()
+
+	video.hw.fbWidth = int32(width)
+	video.hw.fbHeight = int32(height)
+
+	gl.GenTextures(1, &video.hw.tex)
+	gl.BindTexture(gl.TEXTURE_2D, video.hw.tex)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, video.hw.fbWidth, video.hw.fbHeight, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+
+	gl.GenFramebuffers(1, &video.hw.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, video.hw.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, video.hw.tex, 0)
+
+	if video.hw.depth || video.hw.stencil {
+		gl.GenRenderbuffers(1, &video.hw.rbo)
+		gl.BindRenderbuffer(gl.RENDERBUFFER, video.hw.rbo)
+		if video.hw.stencil {
+			gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, video.hw.fbWidth, video.hw.fbHeight)
+			gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, video.hw.rbo)
+		} else {
+			gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH_COMPONENT24, video.hw.fbWidth, video.hw.fbHeight)
+			gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, video.hw.rbo)
+		}
+		gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	}
+
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		log.Printf("[Video]: Incomplete framebuffer: %d\n", status)
+	}
+
+	gl.ClearColor(0, 0, 0, 1)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.BindTexture(gl.TEXTURE_2D, video.texID)
+}
+
+// DestroyFramebuffer deletes the framebuffer object of hardware rendered cores
+func (video *Video) DestroyFramebuffer() {
+	if video.hw.fbo != 0 {
+		gl.DeleteFramebuffers(1, &video.hw.fbo)
+	}
+	if video.hw.rbo != 0 {
+		gl.DeleteRenderbuffers(1, &video.hw.rbo)
+	}
+	if video.hw.tex != 0 {
+		gl.DeleteTextures(1, &video.hw.tex)
+	}
+	video.hw.fbo, video.hw.rbo, video.hw.tex = 0, 0, 0
+	video.hw.valid = false
+}
+
+// ResetHWRender should be called when unloading a game so that the next game
+// won't be rendered from the framebuffer of the previous one
+func (video *Video) ResetHWRender() {
+	if video.headless {
+		return
+	}
+	video.DestroyFramebuffer()
+	video.hw = hwRender{}
+}
+
+// CurrentFramebuffer returns the framebuffer object the core has to render to.
+// It is passed to the core as get_current_framebuffer.
+func (video *Video) CurrentFramebuffer() uintptr {
+	return uintptr(video.hw.fbo)
+}
+
+// ProcAddress returns the address of an OpenGL function. It is passed to the
+// core as get_proc_address.
+func (video *Video) ProcAddress(sym string) unsafe.Pointer {
+	return glfw.GetProcAddress(sym)
+}
+
+// hwTexCoords adjusts the texture coordinates of the game quad to the part of
+// the framebuffer texture used by the core
+func (video *Video) hwTexCoords(va []float32) []float32 {
+	su := float32(video.width) / float32(video.hw.fbWidth)
+	sv := float32(video.height) / float32(video.hw.fbHeight)
+	for i := 2; i < len(va); i += 4 {
+		if video.hw.bottomLeftOrigin {
+			va[i+1] = 1 - va[i+1]
+		}
+		va[i] *= su
+		va[i+1] *= sv
+	}
+	return va
+}
+
+// restoreState restores the parts of the OpenGL state that a hardware rendered
+// core may have changed during retro_run
+func (video *Video) restoreState() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Disable(gl.DEPTH_TEST)
+	gl.Disable(gl.STENCIL_TEST)
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.Disable(gl.CULL_FACE)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, video.vbo)
+	video.ResizeViewport()
+}
+
+// contextDestroy lets a hardware rendered core free its OpenGL resources
+// before the window and its context are destroyed
+func (video *Video) contextDestroy() {
+	if !video.hw.enabled || state.Core == nil || state.Core.HWRenderCallback == nil {
+		return
+	}
+	state.Core.HWRenderCallback.ContextDestroy()
+	// The objects are deleted along with the context
+	video.hw.fbo, video.hw.rbo, video.hw.tex = 0, 0, 0
+	video.hw.valid = false
+}
+
+// contextReset recreates the framebuffer in the new context and lets the core
+// recreate its OpenGL resources
+func (video *Video) contextReset() {
+	if !video.hw.enabled || state.Core == nil || state.Core.HWRenderCallback == nil {
+		return
+	}
+	video.InitFramebuffer(int(video.hw.fbWidth), int(video.hw.fbHeight))
+	state.Core.HWRenderCallback.ContextReset()
+}
@@ -0,0 +1,52 @@
+package video
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_hwTexCoords(t *testing.T) {
+	tests := []struct {
+		name             string
+		bottomLeftOrigin bool
+		want             []float32
+	}{
+		{
+			name:             "Top left origin",
+			bottomLeftOrigin: false,
+			want: []float32{
+				0, 0, 0, 0.25,
+				0, 0, 0, 0,
+				0, 0, 0.5, 0.25,
+				0, 0, 0.5, 0,
+			},
+		},
+		{
+			name:             "Bottom left origin",
+			bottomLeftOrigin: true,
+			want: []float32{
+				0, 0, 0, 0,
+				0, 0, 0, 0.25,
+				0, 0, 0.5, 0,
+				0, 0, 0.5, 0.25,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &Video{width: 320, height: 240}
+			video.hw.fbWidth = 640
+			video.hw.fbHeight = 960
+			video.hw.bottomLeftOrigin = tt.bottomLeftOrigin
+			va := []float32{
+				0, 0, 0, 1,
+				0, 0, 0, 0,
+				0, 0, 1, 1,
+				0, 0, 1, 0,
+			}
+			if got := video.hwTexCoords(va); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hwTexCoords() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package video
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/go-gl/gl/v2.1/gl"
+)
+
+var identity = [16]float32{
+	1, 0, 0, 0,
+	0, 1, 0, 0,
+	0, 0, 1, 0,
+	0, 0, 0, 1,
+}
+
+// Vertex arrays used between the passes of a preset. The textures of the
+// intermediate passes are upside down, their texture coordinates are flipped.
+var passVertices = []float32{
+	//  X, Y, U, V
+	-1.0, -1.0, 0.0, 0.0, // left-bottom
+	-1.0, 1.0, 0.0, 1.0, // left-top
+	1.0, -1.0, 1.0, 0.0, // right-bottom
+	1.0, 1.0, 1.0, 1.0, // right-top
+}
+
+// loadPreset parses a .glslp file and compiles the programs of its passes
+func (video *Video) loadPreset(path string) (*preset, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	p, err := parsePreset(fd, filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	p.path = path
+
+	for _, pass := range p.passes {
+		src, err := ioutil.ReadFile(pass.shader)
+		if err != nil {
+			video.freePreset(p)
+			return nil, err
+		}
+		pass.program, err = newProgram(shaderSource(string(src), "VERTEX"), shaderSource(string(src), "FRAGMENT"))
+		if err != nil {
+			video.freePreset(p)
+			return nil, fmt.Errorf("%s: %v", filepath.Base(pass.shader), err)
+		}
+	}
+
+	if video.presetVAO == 0 {
+		genVertexArrays(1, &video.presetVAO)
+	}
+
+	return p, nil
+}
+
+// freePreset deletes the programs and framebuffers of a preset
+func (video *Video) freePreset(p *preset) {
+	for _, pass := range p.passes {
+		if pass.program != 0 {
+			gl.DeleteProgram(pass.program)
+		}
+		if pass.fbo != 0 {
+			gl.DeleteFramebuffers(1, &pass.fbo)
+		}
+		if pass.tex != 0 {
+			gl.DeleteTextures(1, &pass.tex)
+		}
+	}
+}
+
+// resize creates or resizes the framebuffer a pass renders to
+func (pass *shaderPass) resize(w, h int32) {
+	if pass.fbo != 0 && pass.width == w && pass.height == h {
+		return
+	}
+	if pass.fbo == 0 {
+		gl.GenTextures(1, &pass.tex)
+		gl.GenFramebuffers(1, &pass.fbo)
+	}
+
+	gl.BindTexture(gl.TEXTURE_2D, pass.tex)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, w, h, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, pass.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, pass.tex, 0)
+
+	pass.width, pass.height = w, h
+}
+
+// passTexture is a texture sampled by a pass, with its sizes
+type passTexture struct {
+	id                 uint32
+	inputW, inputH     float32 // size of the image in the texture
+	textureW, textureH float32 // size of the texture
+}
+
+// bindAttribs points the attributes of a pass program to the vertex buffer.
+// Both the RetroArch and the Ludo attribute names are supported.
+func bindAttribs(program uint32) {
+	for i := uint32(0); i < 4; i++ {
+		gl.DisableVertexAttribArray(i)
+	}
+	attribs := []struct {
+		names  []string
+		offset int
+	}{
+		{[]string{"VertexCoord", "vert"}, 0},
+		{[]string{"TexCoord", "vertTexCoord"}, 2 * 4},
+	}
+	for _, a := range attribs {
+		for _, name := range a.names {
+			loc := gl.GetAttribLocation(program, gl.Str(name+"\x00"))
+			if loc < 0 {
+				continue
+			}
+			gl.EnableVertexAttribArray(uint32(loc))
+			gl.VertexAttribPointer(uint32(loc), 2, gl.FLOAT, false, 4*4, gl.PtrOffset(a.offset))
+		}
+	}
+	if loc := gl.GetAttribLocation(program, gl.Str("COLOR\x00")); loc >= 0 {
+		gl.VertexAttrib4f(uint32(loc), 1, 1, 1, 1)
+	}
+}
+
+// renderPreset draws the game through the passes of the current preset. The
+// last pass draws to the window, in the viewport of the game.
+func (video *Video) renderPreset(x, y, w, h float32) {
+	video.frameCount++
+
+	game := video.gameVertexArray(x, y, w, h)
+
+	orig := passTexture{video.texID, float32(video.width), float32(video.height), float32(video.width), float32(video.height)}
+	if video.hw.valid {
+		orig.id = video.hw.tex
+		orig.textureW, orig.textureH = float32(video.hw.fbWidth), float32(video.hw.fbHeight)
+	}
+
+	bindVertexArray(video.presetVAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, video.vbo)
+
+	input := orig
+	prev := []passTexture{}
+	for i, pass := range video.preset.passes {
+		last := i == len(video.preset.passes)-1
+
+		va := make([]float32, len(passVertices))
+		copy(va, passVertices)
+		for v := 0; v < 4; v++ {
+			if last {
+				va[v*4], va[v*4+1] = game[v*4], game[v*4+1]
+			}
+			if i == 0 {
+				va[v*4+2], va[v*4+3] = game[v*4+2], game[v*4+3]
+			}
+		}
+
+		outW, outH := int32(w), int32(h)
+		if last {
+			gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+			video.ResizeViewport()
+		} else {
+			outW, outH = pass.size(input.inputW, input.inputH, w, h)
+			pass.resize(outW, outH)
+			gl.BindFramebuffer(gl.FRAMEBUFFER, pass.fbo)
+			gl.Viewport(0, 0, outW, outH)
+			gl.ClearColor(0, 0, 0, 1)
+			gl.Clear(gl.COLOR_BUFFER_BIT)
+		}
+
+		gl.UseProgram(pass.program)
+		uniform := func(name string) int32 {
+			return gl.GetUniformLocation(pass.program, gl.Str(name+"\x00"))
+		}
+		gl.UniformMatrix4fv(uniform("MVPMatrix"), 1, false, &identity[0])
+		gl.Uniform1i(uniform("FrameCount"), int32(pass.frameCount(video.frameCount)))
+		gl.Uniform1i(uniform("FrameDirection"), 1)
+		gl.Uniform2f(uniform("OutputSize"), float32(outW), float32(outH))
+		gl.Uniform2f(uniform("InputSize"), input.inputW, input.inputH)
+		gl.Uniform2f(uniform("TextureSize"), input.textureW, input.textureH)
+		gl.Uniform2f(uniform("OrigInputSize"), orig.inputW, orig.inputH)
+		gl.Uniform2f(uniform("OrigTextureSize"), orig.textureW, orig.textureH)
+
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.BindTexture(gl.TEXTURE_2D, input.id)
+		filter := int32(gl.NEAREST)
+		if pass.filterLinear {
+			filter = gl.LINEAR
+		}
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, filter)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, filter)
+		gl.Uniform1i(uniform("Texture"), 0)
+
+		gl.ActiveTexture(gl.TEXTURE1)
+		gl.BindTexture(gl.TEXTURE_2D, orig.id)
+		gl.Uniform1i(uniform("OrigTexture"), 1)
+
+		// PassPrev1 is the input of this pass, PassPrev2 the input of the
+		// previous one, and so on
+		for k := range prev {
+			unit := int32(2 + k)
+			t := prev[len(prev)-1-k]
+			gl.ActiveTexture(gl.TEXTURE0 + uint32(unit))
+			gl.BindTexture(gl.TEXTURE_2D, t.id)
+			gl.Uniform1i(uniform(fmt.Sprintf("PassPrev%dTexture", k+1)), unit)
+			gl.Uniform2f(uniform(fmt.Sprintf("PassPrev%dInputSize", k+1)), t.inputW, t.inputH)
+			gl.Uniform2f(uniform(fmt.Sprintf("PassPrev%dTextureSize", k+1)), t.textureW, t.textureH)
+		}
+		gl.ActiveTexture(gl.TEXTURE0)
+
+		gl.BufferData(gl.ARRAY_BUFFER, len(va)*4, gl.Ptr(va), gl.STATIC_DRAW)
+		bindAttribs(pass.program)
+		gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+
+		if !last {
+			input = passTexture{pass.tex, float32(outW), float32(outH), float32(outW), float32(outH)}
+			prev = append(prev, input)
+		}
+	}
+
+	bindVertexArray(video.vao)
+	gl.UseProgram(video.program)
+}
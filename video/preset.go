@@ -0,0 +1,230 @@
+package video
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/libretro/ludo/settings"
+)
+
+// Scale types of a shader pass
+const (
+	scaleSource   = "source"   // relative to the size of the input of the pass
+	scaleViewport = "viewport" // relative to the size of the game viewport
+	scaleAbsolute = "absolute" // in pixels
+)
+
+// shaderPass is a pass of a shader preset
+type shaderPass struct {
+	shader         string // path of the .glsl file
+	filterLinear   bool   // sample the input of the pass with linear filtering
+	scaleTypeX     string
+	scaleTypeY     string
+	scaleX, scaleY float32
+	frameCountMod  uint // FrameCount is passed modulo this value, if not 0
+
+	program uint32
+	fbo     uint32 // framebuffer the pass renders to, except for the last pass
+	tex     uint32 // texture attached to fbo
+	width   int32  // size of tex
+	height  int32
+}
+
+// preset is a multi-pass shader preset, as described by a .glslp file
+type preset struct {
+	path   string
+	passes []*shaderPass
+}
+
+// parsePreset reads a .glslp file. Paths of the shaders are relative to dir.
+func parsePreset(r io.Reader, dir string) (*preset, error) {
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		value := strings.TrimSpace(kv[1])
+		values[key] = strings.Trim(value, `"`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	count, err := strconv.Atoi(values["shaders"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid shader count: %v", err)
+	}
+	if count < 1 {
+		return nil, fmt.Errorf("invalid shader count: %d", count)
+	}
+
+	p := &preset{}
+	for i := 0; i < count; i++ {
+		get := func(name string) (string, bool) {
+			v, ok := values[fmt.Sprintf("%s%d", name, i)]
+			return v, ok
+		}
+		scale := func(name string) float32 {
+			s, ok := get(name)
+			if !ok {
+				return 0
+			}
+			f, err := strconv.ParseFloat(s, 32)
+			if err != nil {
+				return 0
+			}
+			return float32(f)
+		}
+
+		shader, ok := get("shader")
+		if !ok {
+			return nil, fmt.Errorf("missing shader%d", i)
+		}
+		pass := &shaderPass{shader: filepath.Join(dir, shader)}
+
+		if s, ok := get("filter_linear"); ok {
+			pass.filterLinear = s == "true"
+		}
+		if s, ok := get("frame_count_mod"); ok {
+			n, err := strconv.ParseUint(s, 10, 32)
+			if err == nil {
+				pass.frameCountMod = uint(n)
+			}
+		}
+
+		// The last pass is scaled to the viewport by default, the other passes
+		// keep the size of their input
+		pass.scaleTypeX = scaleSource
+		if i == count-1 {
+			pass.scaleTypeX = scaleViewport
+		}
+		if s, ok := get("scale_type"); ok {
+			pass.scaleTypeX = s
+		}
+		pass.scaleTypeY = pass.scaleTypeX
+		if s, ok := get("scale_type_x"); ok {
+			pass.scaleTypeX = s
+		}
+		if s, ok := get("scale_type_y"); ok {
+			pass.scaleTypeY = s
+		}
+		for _, t := range []string{pass.scaleTypeX, pass.scaleTypeY} {
+			if t != scaleSource && t != scaleViewport && t != scaleAbsolute {
+				return nil, fmt.Errorf("invalid scale type: %s", t)
+			}
+		}
+
+		pass.scaleX, pass.scaleY = 1, 1
+		if s := scale("scale"); s != 0 {
+			pass.scaleX, pass.scaleY = s, s
+		}
+		if s := scale("scale_x"); s != 0 {
+			pass.scaleX = s
+		}
+		if s := scale("scale_y"); s != 0 {
+			pass.scaleY = s
+		}
+
+		p.passes = append(p.passes, pass)
+	}
+
+	return p, nil
+}
+
+// scaledSize computes a dimension of the output of a pass
+func scaledSize(scaleType string, scale, source, viewport float32) int32 {
+	switch scaleType {
+	case scaleViewport:
+		return int32(viewport*scale + 0.5)
+	case scaleAbsolute:
+		return int32(scale)
+	default:
+		return int32(source*scale + 0.5)
+	}
+}
+
+// size computes the size of the output of a pass from the size of its input
+// and the size of the game viewport
+func (pass *shaderPass) size(srcW, srcH, vpW, vpH float32) (int32, int32) {
+	w := scaledSize(pass.scaleTypeX, pass.scaleX, srcW, vpW)
+	h := scaledSize(pass.scaleTypeY, pass.scaleY, srcH, vpH)
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	return w, h
+}
+
+// frameCount returns the FrameCount uniform of a pass
+func (pass *shaderPass) frameCount(count uint) uint {
+	if pass.frameCountMod != 0 {
+		return count % pass.frameCountMod
+	}
+	return count
+}
+
+// shaderSource prepares a RetroArch GLSL shader for the given stage, VERTEX or
+// FRAGMENT. Both stages are in the same file, the stage is selected by a
+// define that has to come after the #version directive.
+func shaderSource(src, stage string) string {
+	version := ""
+	trimmed := strings.TrimLeft(src, " \t\r\n")
+	if strings.HasPrefix(trimmed, "#version") {
+		i := strings.Index(trimmed, "\n")
+		if i < 0 {
+			i = len(trimmed)
+		}
+		version = trimmed[:i] + "\n"
+		src = trimmed[i:]
+	}
+	return version + "#define " + stage + "\n" + src + "\x00"
+}
+
+// presetPath returns the path of a preset. Relative paths are relative to the
+// shaders directory.
+func presetPath(filter string) string {
+	if filepath.IsAbs(filter) {
+		return filter
+	}
+	return filepath.Join(settings.Current.ShadersDirectory, filter)
+}
+
+// isPreset tells if a video filter is a shader preset rather than a built-in
+// filter
+func isPreset(filter string) bool {
+	return filepath.Ext(filter) == ".glslp"
+}
+
+// Presets lists the shader presets found in the shaders directory, relative to
+// the shaders directory
+func Presets() []string {
+	presets := []string{}
+	root := settings.Current.ShadersDirectory
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !isPreset(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		presets = append(presets, rel)
+		return nil
+	})
+	return presets
+}
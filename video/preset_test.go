@@ -0,0 +1,143 @@
+package video
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parsePreset(t *testing.T) {
+	t.Run("Parses the passes", func(t *testing.T) {
+		r := strings.NewReader(`# two passes
+shaders = 2
+
+shader0 = "shaders/blur.glsl"
+filter_linear0 = true
+scale_type0 = source
+scale0 = 2.0
+frame_count_mod0 = 100
+
+shader1 = shaders/crt.glsl
+scale_type_y1 = absolute
+scale_y1 = 240
+`)
+		got, err := parsePreset(r, "presets")
+		if err != nil {
+			t.Fatalf("parsePreset() error = %v", err)
+		}
+		want := []*shaderPass{
+			{
+				shader:        filepath.Join("presets", "shaders", "blur.glsl"),
+				filterLinear:  true,
+				scaleTypeX:    scaleSource,
+				scaleTypeY:    scaleSource,
+				scaleX:        2,
+				scaleY:        2,
+				frameCountMod: 100,
+			},
+			{
+				shader:     filepath.Join("presets", "shaders", "crt.glsl"),
+				scaleTypeX: scaleViewport,
+				scaleTypeY: scaleAbsolute,
+				scaleX:     1,
+				scaleY:     240,
+			},
+		}
+		if !reflect.DeepEqual(got.passes, want) {
+			t.Errorf("got = %+v, want %+v", got.passes, want)
+		}
+	})
+
+	t.Run("Fails without shader count", func(t *testing.T) {
+		_, err := parsePreset(strings.NewReader("shader0 = a.glsl\n"), "")
+		if err == nil {
+			t.Errorf("got = %v, want an error", err)
+		}
+	})
+
+	t.Run("Fails with a missing pass", func(t *testing.T) {
+		_, err := parsePreset(strings.NewReader("shaders = 2\nshader0 = a.glsl\n"), "")
+		if err == nil || err.Error() != "missing shader1" {
+			t.Errorf("got = %v, want missing shader1", err)
+		}
+	})
+
+	t.Run("Fails with an invalid scale type", func(t *testing.T) {
+		_, err := parsePreset(strings.NewReader("shaders = 1\nshader0 = a.glsl\nscale_type0 = window\n"), "")
+		if err == nil || err.Error() != "invalid scale type: window" {
+			t.Errorf("got = %v, want invalid scale type: window", err)
+		}
+	})
+}
+
+func Test_shaderPass_size(t *testing.T) {
+	tests := []struct {
+		name  string
+		pass  shaderPass
+		wantW int32
+		wantH int32
+	}{
+		{
+			name:  "Source",
+			pass:  shaderPass{scaleTypeX: scaleSource, scaleTypeY: scaleSource, scaleX: 2, scaleY: 3},
+			wantW: 640,
+			wantH: 720,
+		},
+		{
+			name:  "Viewport",
+			pass:  shaderPass{scaleTypeX: scaleViewport, scaleTypeY: scaleViewport, scaleX: 0.5, scaleY: 1},
+			wantW: 500,
+			wantH: 750,
+		},
+		{
+			name:  "Mixed",
+			pass:  shaderPass{scaleTypeX: scaleAbsolute, scaleTypeY: scaleSource, scaleX: 256, scaleY: 1},
+			wantW: 256,
+			wantH: 240,
+		},
+		{
+			name:  "Never empty",
+			pass:  shaderPass{scaleTypeX: scaleSource, scaleTypeY: scaleAbsolute, scaleX: 0, scaleY: 0},
+			wantW: 1,
+			wantH: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotW, gotH := tt.pass.size(320, 240, 1000, 750)
+			if gotW != tt.wantW || gotH != tt.wantH {
+				t.Errorf("got = %vx%v, want %vx%v", gotW, gotH, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func Test_shaderPass_frameCount(t *testing.T) {
+	pass := shaderPass{frameCountMod: 60}
+	if got := pass.frameCount(125); got != 5 {
+		t.Errorf("got = %v, want %v", got, 5)
+	}
+	pass = shaderPass{}
+	if got := pass.frameCount(125); got != 125 {
+		t.Errorf("got = %v, want %v", got, 125)
+	}
+}
+
+func Test_shaderSource(t *testing.T) {
+	t.Run("Keeps the version first", func(t *testing.T) {
+		got := shaderSource("\n#version 130\nvoid main() {}\n", "VERTEX")
+		want := "#version 130\n#define VERTEX\n\nvoid main() {}\n\x00"
+		if got != want {
+			t.Errorf("got = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Without version", func(t *testing.T) {
+		got := shaderSource("void main() {}\n", "FRAGMENT")
+		want := "#define FRAGMENT\nvoid main() {}\n\x00"
+		if got != want {
+			t.Errorf("got = %q, want %q", got, want)
+		}
+	})
+}
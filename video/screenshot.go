@@ -18,12 +18,19 @@ import (
 // taking care of this.
 func (video *Video) renderScreenshot() {
 	va := video.vertexArray(0, 0, float32(video.Geom.BaseWidth), float32(video.Geom.BaseHeight), 1.0)
+	if video.hw.valid {
+		va = video.hwTexCoords(va)
+	}
 	gl.BindBuffer(gl.ARRAY_BUFFER, video.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(va)*4, gl.Ptr(va), gl.STATIC_DRAW)
 
 	bindVertexArray(video.vao)
 
-	gl.BindTexture(gl.TEXTURE_2D, video.texID)
+	if video.hw.valid {
+		gl.BindTexture(gl.TEXTURE_2D, video.hw.tex)
+	} else {
+		gl.BindTexture(gl.TEXTURE_2D, video.texID)
+	}
 	gl.BindBuffer(gl.ARRAY_BUFFER, video.vbo)
 
 	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
@@ -31,8 +38,8 @@ func (video *Video) renderScreenshot() {
 
 // TakeScreenshot captures the ouput of video.Render and writes it to a file
 func (video *Video) TakeScreenshot(name string) error {
+	defer func(active bool) { state.MenuActive = active }(state.MenuActive)
 	state.MenuActive = false
-	defer func() { state.MenuActive = true }()
 
 	gl.UseProgram(video.defaultProgram)
 
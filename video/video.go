@@ -4,6 +4,7 @@
 package video
 
 import (
+	"image"
 	"log"
 	"path/filepath"
 	"unsafe"
@@ -11,6 +12,7 @@ import (
 	"github.com/go-gl/gl/v2.1/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/recording"
 	"github.com/libretro/ludo/settings"
 	"github.com/libretro/ludo/state"
 )
@@ -21,6 +23,8 @@ type Video struct {
 	Geom   libretro.GameGeometry
 	Font   *Font
 
+	CoreName string // library name of the running core, for its display options
+
 	program              uint32 // current program used for the game quad
 	defaultProgram       uint32 // default program used for the game quad
 	sharpBilinearProgram uint32 // sharp bilinear program used for the game quad
@@ -35,11 +39,21 @@ type Video struct {
 	texID                uint32
 
 	pitch         int32  // pitch set by the refresh callback
-	pixFmt        uint32 // format set by the environment callback
+	format        uint32 // libretro pixel format set by the environment callback
+	pixFmt        uint32 // GL pixel format matching format
 	pixType       uint32
 	bpp           int32
 	width, height int32 // dimensions set by the refresh callback
 	rot           uint
+
+	hw hwRender // framebuffer of hardware rendered cores
+
+	preset     *preset // shader preset used instead of program, if any
+	presetVAO  uint32
+	frameCount uint // number of frames rendered, passed to the preset shaders
+
+	headless bool        // true if running without a window and GL context
+	frame    *image.RGBA // last frame received in headless mode
 }
 
 // Init instanciates the video package
@@ -49,12 +63,20 @@ func Init(fullscreen bool) *Video {
 	return vid
 }
 
+// InitHeadless instanciates the video package without creating a window. The
+// frames are kept in memory and can be retrieved with Frame.
+func InitHeadless() *Video {
+	return &Video{headless: true}
+}
+
 // Reconfigure destroys and recreates the window with new attributes
 func (video *Video) Reconfigure(fullscreen bool) {
+	video.contextDestroy()
 	if video.Window != nil {
 		video.Window.Destroy()
 	}
 	video.Configure(fullscreen)
+	video.contextReset()
 }
 
 // GetFramebufferSize retrieves the size, in pixels, of the framebuffer of the specified window.
@@ -81,6 +103,18 @@ func (video *Video) SetShouldClose(b bool) {
 	video.Window.SetShouldClose(b)
 }
 
+// RefreshRate returns the refresh rate of the monitor showing the window
+func (video *Video) RefreshRate() float64 {
+	m := video.Window.GetMonitor()
+	if m == nil {
+		m = glfw.GetPrimaryMonitor()
+	}
+	if m == nil {
+		return 0
+	}
+	return float64(m.GetVideoMode().RefreshRate)
+}
+
 // Configure instanciates the video package
 func (video *Video) Configure(fullscreen bool) {
 	var width, height int
@@ -97,6 +131,10 @@ func (video *Video) Configure(fullscreen bool) {
 		height = 180 * 3
 	}
 
+	// Objects of the previous context are gone with it
+	video.preset = nil
+	video.presetVAO = 0
+
 	var err error
 	video.Window, err = glfw.CreateWindow(width, height, "Ludo", m, nil)
 	if err != nil {
@@ -212,13 +250,29 @@ func (video *Video) Configure(fullscreen bool) {
 }
 
 // UpdateFilter configures the game texture filter and shader. We currently
-// support 4 modes:
+// support 5 modes:
 // Raw: nearest
 // Smooth: linear
 // Pixel Perfect: sharp-bilinear
 // CRT: zfast-crt
 // LCD: zfast-lcd
+// The filter can also be the path of a .glslp shader preset, relative to the
+// shaders directory.
 func (video *Video) UpdateFilter(filter string) {
+	if video.preset != nil {
+		video.freePreset(video.preset)
+		video.preset = nil
+	}
+	if isPreset(filter) {
+		p, err := video.loadPreset(presetPath(filter))
+		if err != nil {
+			log.Println("[Video]: Failed to load shader preset:", err)
+			filter = "Raw"
+		} else {
+			video.preset = p
+		}
+	}
+
 	gl.BindTexture(gl.TEXTURE_2D, video.texID)
 	switch filter {
 	case "Smooth":
@@ -258,21 +312,33 @@ func (video *Video) SetPixelFormat(format uint32) bool {
 		log.Printf("[Video]: Set Pixel Format: %v\n", format)
 	}
 
+	if video.headless {
+		switch format {
+		case libretro.PixelFormat0RGB1555, libretro.PixelFormatXRGB8888, libretro.PixelFormatRGB565:
+			video.format = format
+			return true
+		}
+		return false
+	}
+
 	// PixelStorei also needs to be updated whenever bpp changes
 	defer gl.PixelStorei(gl.UNPACK_ROW_LENGTH, video.pitch/video.bpp)
 
 	switch format {
 	case libretro.PixelFormat0RGB1555:
+		video.format = format
 		video.pixFmt = gl.UNSIGNED_SHORT_5_5_5_1
 		video.pixType = gl.BGRA
 		video.bpp = 2
 		return true
 	case libretro.PixelFormatXRGB8888:
+		video.format = format
 		video.pixFmt = gl.UNSIGNED_INT_8_8_8_8_REV
 		video.pixType = gl.BGRA
 		video.bpp = 4
 		return true
 	case libretro.PixelFormatRGB565:
+		video.format = format
 		video.pixFmt = gl.UNSIGNED_SHORT_5_6_5
 		video.pixType = gl.RGB
 		video.bpp = 2
@@ -299,33 +365,65 @@ func (video *Video) ResetRot() {
 // coreRatioViewport configures the vertex array to display the game at the center of the window
 // while preserving the original ascpect ratio of the game or core
 func (video *Video) coreRatioViewport(fbWidth int, fbHeight int) (x, y, w, h float32) {
-	// Scale the content to fit in the viewport.
-	fbw := float32(fbWidth)
-	fbh := float32(fbHeight)
+	x, y, w, h = video.gameRect(fbWidth, fbHeight)
 
-	// NXEngine workaround
-	aspectRatio := float32(video.Geom.AspectRatio)
-	if aspectRatio == 0 {
-		aspectRatio = float32(video.Geom.BaseWidth) / float32(video.Geom.BaseHeight)
-	}
+	va := video.gameVertexArray(x, y, w, h)
+	gl.BindBuffer(gl.ARRAY_BUFFER, video.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(va)*4, gl.Ptr(va), gl.STATIC_DRAW)
 
-	h = fbh
-	w = fbh * aspectRatio
-	if w > fbw {
-		h = fbw / aspectRatio
-		w = fbw
-	}
+	return
+}
+
+// display returns the display options and the overscan crop of the running
+// core
+func (video *Video) display() (settings.Display, settings.Crop) {
+	return settings.DisplayFor(video.CoreName), settings.Current.OverscanCrop[video.CoreName]
+}
 
-	// Place the content in the middle of the window.
-	x = (fbw - w) / 2
-	y = (fbh - h) / 2
+// gameRect returns the area of the framebuffer where the game is displayed,
+// centered and with the aspect ratio of the display options
+func (video *Video) gameRect(fbWidth int, fbHeight int) (x, y, w, h float32) {
+	d, c := video.display()
+	return viewport(fbWidth, fbHeight, video.Geom, d, c)
+}
+
+// CursorToCore converts a cursor position, in window coordinates, to
+// coordinates in the game frame between 0 and 1. The game viewport and the
+// rotation are taken into account. inside is false if the cursor is outside
+// of the game viewport.
+func (video *Video) CursorToCore(cx, cy float64) (u, v float32, inside bool) {
+	if video.headless {
+		return 0, 0, false
+	}
+	winW, winH := video.Window.GetSize()
+	fbw, fbh := video.Window.GetFramebufferSize()
+	if winW == 0 || winH == 0 {
+		return 0, 0, false
+	}
+	// The framebuffer is bigger than the window on HiDPI screens
+	px := float32(cx) * float32(fbw) / float32(winW)
+	py := float32(cy) * float32(fbh) / float32(winH)
+	x, y, w, h := video.gameRect(fbw, fbh)
+	u, v, inside = viewportToCore(px, py, x, y, w, h, video.rot)
+
+	// The coordinates are in the cropped image
+	d, c := video.display()
+	u0, v0, u1, v1 := cropUV(video.Geom, d, c)
+	return u0 + u*(u1-u0), v0 + v*(v1-v0), inside
+}
 
+// gameVertexArray returns the vertex array of the game quad, with the texture
+// coordinates matching the rotation and the source of the frame
+func (video *Video) gameVertexArray(x, y, w, h float32) []float32 {
 	va := video.vertexArray(x, y, w, h, 1.0)
 	va = rotateUV(va, video.rot)
-	gl.BindBuffer(gl.ARRAY_BUFFER, video.vbo)
-	gl.BufferData(gl.ARRAY_BUFFER, len(va)*4, gl.Ptr(va), gl.STATIC_DRAW)
-
-	return
+	d, c := video.display()
+	u0, v0, u1, v1 := cropUV(video.Geom, d, c)
+	va = cropTexCoords(va, u0, v0, u1, v1)
+	if video.hw.valid {
+		va = video.hwTexCoords(va)
+	}
+	return va
 }
 
 // ResizeViewport resizes the GL viewport to the framebuffer size
@@ -336,6 +434,14 @@ func (video *Video) ResizeViewport() {
 
 // Render the current frame
 func (video *Video) Render() {
+	if video.headless {
+		return
+	}
+
+	if video.hw.enabled {
+		video.restoreState()
+	}
+
 	if !state.CoreRunning {
 		gl.ClearColor(1, 1, 1, 1)
 		gl.Clear(gl.COLOR_BUFFER_BIT)
@@ -351,14 +457,23 @@ func (video *Video) Render() {
 	}
 
 	fbw, fbh := video.Window.GetFramebufferSize()
-	_, _, w, h := video.coreRatioViewport(fbw, fbh)
+	x, y, w, h := video.coreRatioViewport(fbw, fbh)
+
+	if video.preset != nil {
+		video.renderPreset(x, y, w, h)
+		return
+	}
 
 	gl.UseProgram(video.program)
 	gl.Uniform2f(gl.GetUniformLocation(video.program, gl.Str("OutputSize\x00")), w, h)
 
 	bindVertexArray(video.vao)
 
-	gl.BindTexture(gl.TEXTURE_2D, video.texID)
+	if video.hw.valid {
+		gl.BindTexture(gl.TEXTURE_2D, video.hw.tex)
+	} else {
+		gl.BindTexture(gl.TEXTURE_2D, video.texID)
+	}
 	gl.BindBuffer(gl.ARRAY_BUFFER, video.vbo)
 
 	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
@@ -370,6 +485,30 @@ func (video *Video) Refresh(data unsafe.Pointer, width int32, height int32, pitc
 	video.height = height
 	video.pitch = pitch
 
+	if video.headless {
+		video.refreshHeadless(data)
+		return
+	}
+
+	if recording.Active() {
+		video.recordFrame(data)
+	}
+
+	// Hardware rendered frames are already in the framebuffer texture
+	if libretro.IsHWFrameBufferValid(data) {
+		video.hw.valid = true
+		video.pitch = width * 4
+		gl.UseProgram(video.program)
+		gl.Uniform2f(gl.GetUniformLocation(video.program, gl.Str("TextureSize\x00")), float32(video.hw.fbWidth), float32(video.hw.fbHeight))
+		gl.Uniform2f(gl.GetUniformLocation(video.program, gl.Str("InputSize\x00")), float32(width), float32(height))
+		return
+	}
+	if data == nil && video.hw.valid {
+		video.pitch = width * 4
+		return
+	}
+	video.hw.valid = false
+
 	gl.BindTexture(gl.TEXTURE_2D, video.texID)
 	gl.PixelStorei(gl.UNPACK_ROW_LENGTH, video.pitch/video.bpp)
 
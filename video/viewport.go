@@ -0,0 +1,113 @@
+package video
+
+import (
+	"math"
+
+	"github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/settings"
+)
+
+// AspectModes lists the aspect ratios the game image can be displayed with
+var AspectModes = []string{"Core", "4:3", "16:9", "1:1 PAR", "Custom"}
+
+// visibleSize returns the size of the game image in pixels of the base
+// geometry, once the overscan is cropped. The crop is ignored if it would hide
+// the whole image.
+func visibleSize(geom libretro.GameGeometry, d settings.Display, c settings.Crop) (vw, vh float32, crop settings.Crop) {
+	vw, vh = float32(geom.BaseWidth), float32(geom.BaseHeight)
+	if !d.CropOverscan || c.Left < 0 || c.Right < 0 || c.Top < 0 || c.Bottom < 0 ||
+		c.Left+c.Right >= geom.BaseWidth || c.Top+c.Bottom >= geom.BaseHeight {
+		return vw, vh, settings.Crop{}
+	}
+	return vw - float32(c.Left+c.Right), vh - float32(c.Top+c.Bottom), c
+}
+
+// cropUV returns the part of the frame that is displayed, in texture
+// coordinates
+func cropUV(geom libretro.GameGeometry, d settings.Display, c settings.Crop) (u0, v0, u1, v1 float32) {
+	_, _, c = visibleSize(geom, d, c)
+	if c == (settings.Crop{}) {
+		return 0, 0, 1, 1
+	}
+	bw, bh := float32(geom.BaseWidth), float32(geom.BaseHeight)
+	return float32(c.Left) / bw, float32(c.Top) / bh, 1 - float32(c.Right)/bw, 1 - float32(c.Bottom)/bh
+}
+
+// aspectRatio returns the aspect ratio of the displayed game image
+func aspectRatio(geom libretro.GameGeometry, d settings.Display, c settings.Crop) float32 {
+	vw, vh, _ := visibleSize(geom, d, c)
+
+	// NXEngine workaround
+	core := float32(geom.AspectRatio)
+	if core == 0 {
+		core = float32(geom.BaseWidth) / float32(geom.BaseHeight)
+	}
+	// Cropping keeps the shape of the pixels
+	if geom.BaseWidth > 0 && geom.BaseHeight > 0 {
+		core *= (vw / float32(geom.BaseWidth)) / (vh / float32(geom.BaseHeight))
+	}
+
+	var ratio float32
+	switch d.Aspect {
+	case "4:3":
+		ratio = 4.0 / 3.0
+	case "16:9":
+		ratio = 16.0 / 9.0
+	case "1:1 PAR":
+		ratio = vw / vh
+	case "Custom":
+		ratio = d.CustomAspect
+	}
+	if ratio <= 0 || math.IsNaN(float64(ratio)) || math.IsInf(float64(ratio), 0) {
+		ratio = core
+	}
+	if ratio <= 0 || math.IsNaN(float64(ratio)) || math.IsInf(float64(ratio), 0) {
+		ratio = 4.0 / 3.0
+	}
+	return ratio
+}
+
+// viewport returns the area of a framebuffer of fbWidth×fbHeight where the game
+// is displayed, centered. With integer scaling, the height of the image is a
+// multiple of the height of the game, if the framebuffer is big enough.
+func viewport(fbWidth, fbHeight int, geom libretro.GameGeometry, d settings.Display, c settings.Crop) (x, y, w, h float32) {
+	fbw := float32(fbWidth)
+	fbh := float32(fbHeight)
+	ratio := aspectRatio(geom, d, c)
+
+	if _, vh, _ := visibleSize(geom, d, c); d.IntegerScale && vh > 0 {
+		for n := float32(math.Floor(float64(fbh / vh))); n >= 1; n-- {
+			h = vh * n
+			w = float32(math.Round(float64(h * ratio)))
+			if w <= fbw {
+				x = float32(math.Floor(float64(fbw-w) / 2))
+				y = float32(math.Floor(float64(fbh-h) / 2))
+				return
+			}
+		}
+	}
+
+	// Scale the content to fit in the viewport.
+	h = fbh
+	w = fbh * ratio
+	if w > fbw {
+		h = fbw / ratio
+		w = fbw
+	}
+
+	// Place the content in the middle of the window.
+	x = (fbw - w) / 2
+	y = (fbh - h) / 2
+
+	return
+}
+
+// cropTexCoords maps the texture coordinates of a vertex array to the part of
+// the frame that is displayed
+func cropTexCoords(va []float32, u0, v0, u1, v1 float32) []float32 {
+	for i := 2; i < len(va); i += 4 {
+		va[i] = u0 + va[i]*(u1-u0)
+		va[i+1] = v0 + va[i+1]*(v1-v0)
+	}
+	return va
+}
@@ -0,0 +1,147 @@
+package video
+
+import (
+	"math"
+	"testing"
+
+	"github.com/libretro/ludo/libretro"
+	"github.com/libretro/ludo/settings"
+)
+
+func Test_viewport(t *testing.T) {
+	snes := libretro.GameGeometry{BaseWidth: 256, BaseHeight: 224, AspectRatio: 4.0 / 3.0}
+	nes := libretro.GameGeometry{BaseWidth: 256, BaseHeight: 240, AspectRatio: 4.0 / 3.0}
+	nxengine := libretro.GameGeometry{BaseWidth: 320, BaseHeight: 240}
+	crop := settings.Crop{Left: 8, Right: 8, Top: 8, Bottom: 8}
+
+	type args struct {
+		fbw, fbh int
+		geom     libretro.GameGeometry
+		d        settings.Display
+		c        settings.Crop
+	}
+	tests := []struct {
+		name       string
+		args       args
+		x, y, w, h float32
+	}{
+		{
+			name: "Fits the core aspect ratio",
+			args: args{1920, 1080, snes, settings.Display{Aspect: "Core"}, settings.Crop{}},
+			x:    240, y: 0, w: 1440, h: 1080,
+		},
+		{
+			name: "Uses the base geometry without aspect ratio",
+			args: args{1920, 1080, nxengine, settings.Display{Aspect: "Core"}, settings.Crop{}},
+			x:    240, y: 0, w: 1440, h: 1080,
+		},
+		{
+			name: "Fits 16:9 in a square window",
+			args: args{1000, 1000, snes, settings.Display{Aspect: "16:9"}, settings.Crop{}},
+			x:    0, y: 218.75, w: 1000, h: 562.5,
+		},
+		{
+			name: "Uses square pixels",
+			args: args{1280, 896, snes, settings.Display{Aspect: "1:1 PAR"}, settings.Crop{}},
+			x:    128, y: 0, w: 1024, h: 896,
+		},
+		{
+			name: "Uses the custom aspect ratio",
+			args: args{1000, 1000, snes, settings.Display{Aspect: "Custom", CustomAspect: 2}, settings.Crop{}},
+			x:    0, y: 250, w: 1000, h: 500,
+		},
+		{
+			name: "Falls back to the core aspect ratio without custom aspect ratio",
+			args: args{1920, 1080, snes, settings.Display{Aspect: "Custom"}, settings.Crop{}},
+			x:    240, y: 0, w: 1440, h: 1080,
+		},
+		{
+			name: "Scales by an integer factor",
+			args: args{1920, 1080, snes, settings.Display{Aspect: "Core", IntegerScale: true}, settings.Crop{}},
+			x:    362, y: 92, w: 1195, h: 896,
+		},
+		{
+			name: "Lowers the integer factor to fit the width",
+			args: args{700, 1080, snes, settings.Display{Aspect: "Core", IntegerScale: true}, settings.Crop{}},
+			x:    51, y: 316, w: 597, h: 448,
+		},
+		{
+			name: "Fits windows smaller than the game",
+			args: args{200, 150, snes, settings.Display{Aspect: "Core", IntegerScale: true}, settings.Crop{}},
+			x:    0, y: 0, w: 200, h: 150,
+		},
+		{
+			name: "Ignores the crop when the overscan is shown",
+			args: args{1280, 960, nes, settings.Display{Aspect: "1:1 PAR"}, crop},
+			x:    128, y: 0, w: 1024, h: 960,
+		},
+		{
+			name: "Crops the overscan",
+			args: args{1200, 896, nes, settings.Display{Aspect: "1:1 PAR", CropOverscan: true}, crop},
+			x:    120, y: 0, w: 960, h: 896,
+		},
+		{
+			name: "Scales the cropped image by an integer factor",
+			args: args{1920, 1080, nes, settings.Display{Aspect: "1:1 PAR", IntegerScale: true, CropOverscan: true}, crop},
+			x:    480, y: 92, w: 960, h: 896,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, w, h := viewport(tt.args.fbw, tt.args.fbh, tt.args.geom, tt.args.d, tt.args.c)
+			got := []float32{x, y, w, h}
+			want := []float32{tt.x, tt.y, tt.w, tt.h}
+			for i := range got {
+				if math.Abs(float64(got[i]-want[i])) > 0.01 {
+					t.Errorf("viewport() = %v, want %v", got, want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func Test_cropUV(t *testing.T) {
+	nes := libretro.GameGeometry{BaseWidth: 256, BaseHeight: 240, AspectRatio: 4.0 / 3.0}
+	tests := []struct {
+		name string
+		d    settings.Display
+		c    settings.Crop
+		want [4]float32
+	}{
+		{"Shows the whole frame", settings.Display{}, settings.Crop{Top: 8}, [4]float32{0, 0, 1, 1}},
+		{"Crops the borders", settings.Display{CropOverscan: true}, settings.Crop{Left: 16, Top: 24, Right: 32, Bottom: 48}, [4]float32{0.0625, 0.1, 0.875, 0.8}},
+		{"Ignores a crop bigger than the frame", settings.Display{CropOverscan: true}, settings.Crop{Left: 128, Right: 128}, [4]float32{0, 0, 1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u0, v0, u1, v1 := cropUV(nes, tt.d, tt.c)
+			if got := [4]float32{u0, v0, u1, v1}; got != tt.want {
+				t.Errorf("cropUV() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_aspectRatio(t *testing.T) {
+	nes := libretro.GameGeometry{BaseWidth: 256, BaseHeight: 240, AspectRatio: 4.0 / 3.0}
+	d := settings.Display{Aspect: "Core", CropOverscan: true}
+	got := aspectRatio(nes, d, settings.Crop{Left: 8, Right: 8, Top: 8, Bottom: 8})
+	// 240x224 of the 256x240 frame, with the pixels of a 4:3 frame
+	want := float32(4.0 / 3.0 * (240.0 / 256.0) / (224.0 / 240.0))
+	if math.Abs(float64(got-want)) > 0.0001 {
+		t.Errorf("aspectRatio() = %v, want %v", got, want)
+	}
+}
+
+func Test_cropTexCoords(t *testing.T) {
+	va := []float32{0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 1, 1, 0, 0, 1, 0}
+	got := cropTexCoords(va, 0.25, 0.5, 0.75, 1)
+	want := []float32{0, 0, 0.25, 1, 0, 0, 0.25, 0.5, 0, 0, 0.75, 1, 0, 0, 0.75, 0.5}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("cropTexCoords() = %v, want %v", got, want)
+			break
+		}
+	}
+}